@@ -1,6 +1,10 @@
 package parcom
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
 
 // Char creates a parser to parse a character.
 func (s *State) Char(r rune) Parser {
@@ -48,6 +52,90 @@ func (s *State) NotChars(str string) Parser {
 	}
 }
 
+// Satisfy creates a parser to parse a character which satisfies a given
+// predicate.
+func (s *State) Satisfy(f func(rune) bool) Parser {
+	return func() (interface{}, error) {
+		if s.exhausted() || !f(s.currentRune()) {
+			return nil, newInvalidCharacterError(s)
+		}
+
+		defer s.readRune()
+		return s.currentRune(), nil
+	}
+}
+
+// InTable creates a parser to parse a character in any of given Unicode range
+// tables.
+func (s *State) InTable(ts ...*unicode.RangeTable) Parser {
+	return s.Satisfy(func(r rune) bool { return unicode.IsOneOf(ts, r) })
+}
+
+// Range creates a parser to parse a character between given characters
+// inclusively.
+func (s *State) Range(l, h rune) Parser {
+	return s.expect(
+		fmt.Sprintf("character in range '%c'-'%c'", l, h),
+		func(r rune) bool { return l <= r && r <= h },
+	)
+}
+
+// Letter creates a parser to parse a Unicode letter.
+func (s *State) Letter() Parser {
+	return s.expect("letter", unicode.IsLetter)
+}
+
+// Digit creates a parser to parse a Unicode decimal digit.
+func (s *State) Digit() Parser {
+	return s.expect("digit", unicode.IsDigit)
+}
+
+// Space creates a parser to parse a Unicode white space character.
+func (s *State) Space() Parser {
+	return s.expect("space", unicode.IsSpace)
+}
+
+// Upper creates a parser to parse a Unicode upper case letter.
+func (s *State) Upper() Parser {
+	return s.expect("upper case letter", unicode.IsUpper)
+}
+
+// Lower creates a parser to parse a Unicode lower case letter.
+func (s *State) Lower() Parser {
+	return s.expect("lower case letter", unicode.IsLower)
+}
+
+// AnyChar creates a parser to parse any character.
+func (s *State) AnyChar() Parser {
+	return s.expect("any character", func(rune) bool { return true })
+}
+
+// EOF creates a parser which succeeds only at the end of a source.
+func (s *State) EOF() Parser {
+	return func() (interface{}, error) {
+		if !s.exhausted() {
+			return nil, NewError("expected end of source", s)
+		}
+
+		return nil, nil
+	}
+}
+
+func (s *State) expect(m string, f func(rune) bool) Parser {
+	p := s.Satisfy(f)
+	m = "expected " + m
+
+	return func() (interface{}, error) {
+		x, err := p()
+
+		if err != nil {
+			return nil, NewError(m, s)
+		}
+
+		return x, nil
+	}
+}
+
 // Str creates a parser to parse a string.
 func (s *State) Str(str string) Parser {
 	rs := []rune(str)
@@ -60,6 +148,33 @@ func (s *State) Str(str string) Parser {
 	return s.Stringify(s.And(ps...))
 }
 
+// StrFold is the same as Str but it parses a string case-insensitively under
+// Unicode simple case folding. Its result is a parsed string in a source.
+func (s *State) StrFold(str string) Parser {
+	rs := []rune(str)
+
+	return func() (interface{}, error) {
+		qs := make([]rune, 0, len(rs))
+
+		for _, r := range rs {
+			if s.exhausted() || !equalFold(r, s.currentRune()) {
+				return nil, newInvalidCharacterError(s)
+			}
+
+			qs = append(qs, s.currentRune())
+			s.readRune()
+		}
+
+		return string(qs), nil
+	}
+}
+
+// Keyword creates a parser to parse a string which is not followed by an
+// identifier character parsed by a given parser.
+func (s *State) Keyword(str string, p Parser) Parser {
+	return s.Suffix(s.Str(str), s.NotFollowedBy(p))
+}
+
 // Wrap wraps a parser with parsers which parse something before and after.
 // Resulting parsers' parsing results are ones of the middle parsers.
 func (s *State) Wrap(l, m, r Parser) Parser {
@@ -93,11 +208,16 @@ func (s *State) Many(p Parser) Parser {
 
 		for {
 			ss := *s
-			x, err := p()
+			x, err := s.run(p)
 
 			if err != nil {
-				*s = ss
+				if err := s.backtrack(&ss); err != nil {
+					return nil, err
+				}
+
 				break
+			} else if err := s.checkProgress(&ss); err != nil {
+				return nil, err
 			}
 
 			xs = append(xs, x)
@@ -135,10 +255,13 @@ func (s *State) ExhaustiveMany(p Parser) Parser {
 		xs := []interface{}{}
 
 		for !s.exhausted() {
-			x, err := p()
+			ss := *s
+			x, err := s.run(p)
 
 			if err != nil {
 				return nil, err
+			} else if err := s.checkProgress(&ss); err != nil {
+				return nil, err
 			}
 
 			xs = append(xs, x)
@@ -148,6 +271,213 @@ func (s *State) ExhaustiveMany(p Parser) Parser {
 	}
 }
 
+// Count creates a parser of exactly n repetition of a given parser. A negative
+// n is invalid.
+func (s *State) Count(n int, p Parser) Parser {
+	if n < 0 {
+		return s.invalidRepetition(fmt.Sprintf("invalid repetition count %d", n))
+	}
+
+	return s.Repeat(n, n, p)
+}
+
+// Repeat creates a parser of repetition of a given parser at least min times
+// and at most max times. A negative max means no upper limit.
+func (s *State) Repeat(min, max int, p Parser) Parser {
+	if max >= 0 && min > max {
+		return s.invalidRepetition(fmt.Sprintf("invalid repetition range [%d, %d]", min, max))
+	}
+
+	return func() (interface{}, error) {
+		xs := []interface{}{}
+
+		for max < 0 || len(xs) < max {
+			ss := *s
+			x, err := s.run(p)
+
+			if err != nil {
+				if err := s.backtrack(&ss); err != nil {
+					return nil, err
+				}
+
+				break
+			} else if err := s.checkProgress(&ss); max < 0 && err != nil {
+				return nil, err
+			}
+
+			xs = append(xs, x)
+		}
+
+		if len(xs) < min {
+			return nil, newRawError(
+				fmt.Sprintf("expected at least %d repetitions, got %d", min, len(xs)),
+				s,
+			)
+		}
+
+		return xs, nil
+	}
+}
+
+func (s *State) invalidRepetition(m string) Parser {
+	return func() (interface{}, error) {
+		return nil, newRawError(m, s)
+	}
+}
+
+// SepBy creates a parser of more than or equal to 0 repetition of a given
+// parser separated by a separator parser. Its result is a flat sequence of the
+// element parser's results.
+func (s *State) SepBy(p, sep Parser) Parser {
+	return s.sepBy(p, sep, false, false)
+}
+
+// SepBy1 is the same as SepBy but it parses at least one element.
+func (s *State) SepBy1(p, sep Parser) Parser {
+	return s.sepBy(p, sep, true, false)
+}
+
+// SepEndBy is the same as SepBy but it allows an optional trailing separator.
+func (s *State) SepEndBy(p, sep Parser) Parser {
+	return s.sepBy(p, sep, false, true)
+}
+
+// EndBy creates a parser of more than or equal to 0 repetition of a given
+// parser each of which is followed by a separator parser.
+func (s *State) EndBy(p, sep Parser) Parser {
+	return func() (interface{}, error) {
+		xs := []interface{}{}
+
+		for {
+			ss := *s
+			x, err := s.run(p)
+
+			if err != nil {
+				return xs, s.backtrack(&ss)
+			}
+
+			if _, err := s.run(sep); err != nil {
+				return nil, err
+			} else if err := s.checkProgress(&ss); err != nil {
+				return nil, err
+			}
+
+			xs = append(xs, x)
+		}
+	}
+}
+
+func (s *State) sepBy(p, sep Parser, nonEmpty, trailing bool) Parser {
+	return func() (interface{}, error) {
+		ss := *s
+		x, err := s.run(p)
+
+		if err != nil {
+			if nonEmpty {
+				return nil, err
+			} else if err := s.backtrack(&ss); err != nil {
+				return nil, err
+			}
+
+			return []interface{}{}, nil
+		}
+
+		xs := []interface{}{x}
+
+		for {
+			ss := *s
+
+			if _, err := s.run(sep); err != nil {
+				return xs, s.backtrack(&ss)
+			}
+
+			rr := *s
+			x, err := s.run(p)
+
+			if err != nil {
+				if !trailing {
+					return nil, err
+				}
+
+				return xs, s.backtrack(&rr)
+			} else if err := s.checkProgress(&ss); err != nil {
+				return nil, err
+			}
+
+			xs = append(xs, x)
+		}
+	}
+}
+
+// ManyTill creates a parser of more than or equal to 0 repetition of a given
+// parser until an end parser succeeds. Results of the end parser are discarded.
+func (s *State) ManyTill(p, end Parser) Parser {
+	return func() (interface{}, error) {
+		xs := []interface{}{}
+
+		for {
+			ok, err := s.tryEnd(end)
+
+			if err != nil {
+				return nil, err
+			} else if ok {
+				return xs, nil
+			}
+
+			ss := *s
+			x, err := s.run(p)
+
+			if err != nil {
+				return nil, err
+			} else if err := s.checkProgress(&ss); err != nil {
+				return nil, err
+			}
+
+			xs = append(xs, x)
+		}
+	}
+}
+
+// SkipUntil creates a parser which skips characters until an end parser
+// succeeds.
+func (s *State) SkipUntil(end Parser) Parser {
+	return func() (interface{}, error) {
+		for {
+			ok, err := s.tryEnd(end)
+
+			if err != nil {
+				return nil, err
+			} else if ok {
+				return nil, nil
+			}
+
+			s.readRune()
+		}
+	}
+}
+
+func (s *State) checkProgress(ss *State) error {
+	if s.sourceIndex == ss.sourceIndex {
+		return newRawError("repeated parser succeeded without consuming input", s)
+	}
+
+	return nil
+}
+
+func (s *State) tryEnd(end Parser) (bool, error) {
+	ss := *s
+
+	if _, err := s.run(end); err == nil {
+		return true, nil
+	} else if err := s.backtrack(&ss); err != nil {
+		return false, err
+	} else if s.exhausted() {
+		return false, NewError("unexpected end of source", s)
+	}
+
+	return false, nil
+}
+
 // Or creates a selectional parser from given parsers.
 func (s *State) Or(ps ...Parser) Parser {
 	return func() (interface{}, error) {
@@ -156,16 +486,127 @@ func (s *State) Or(ps ...Parser) Parser {
 
 		for _, p := range ps {
 			var x interface{}
-			x, err = p()
+			x, err = s.run(p)
 
 			if err == nil {
 				return x, nil
+			} else if err := s.backtrack(&ss); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, err
+	}
+}
+
+// Permute creates a parser which parses given required and optional parsers
+// in any order. Each parser must succeed at most once and every required
+// parser must succeed. Its results are ordered as given parsers and results
+// of missing optional parsers are nil. Optional labels of the required and
+// optional parsers in the same order are used in error messages.
+func (s *State) Permute(rs, os []Parser, ls ...string) Parser {
+	ps := append(append([]Parser{}, rs...), os...)
+
+	return func() (interface{}, error) {
+		xs := make([]interface{}, len(ps))
+		ds := make([]bool, len(ps))
+
+		for {
+			i, err := s.permuteOnce(ps, xs, ds, false)
+
+			if err != nil {
+				return nil, err
+			} else if i < 0 {
+				break
 			}
 
+			ds[i] = true
+		}
+
+		if i, err := s.permuteOnce(ps, xs, ds, true); err != nil {
+			return nil, err
+		} else if i >= 0 {
+			return nil, newRawError("duplicate clause "+clauseLabel(ls, i), s)
+		}
+
+		for i := range rs {
+			if !ds[i] {
+				return nil, newRawError("missing clause "+clauseLabel(ls, i), s)
+			}
+		}
+
+		return xs, nil
+	}
+}
+
+// permuteOnce runs parsers not done yet and returns an index of the first
+// successful one. If done is true, it runs parsers done already instead and
+// returns an index of the first one consuming input without consuming it.
+func (s *State) permuteOnce(ps []Parser, xs []interface{}, ds []bool, done bool) (int, error) {
+	for i, p := range ps {
+		if ds[i] != done {
+			continue
+		}
+
+		ss := *s
+		x, err := s.run(p)
+
+		if err != nil {
+			if err := s.backtrack(&ss); err != nil {
+				return -1, err
+			}
+
+			continue
+		} else if done {
+			n := s.sourceIndex - ss.sourceIndex
 			*s = ss
+
+			if n == 0 {
+				continue
+			}
+		} else {
+			xs[i] = x
 		}
 
-		return nil, err
+		return i, nil
+	}
+
+	return -1, nil
+}
+
+func clauseLabel(ls []string, i int) string {
+	if i < len(ls) {
+		return fmt.Sprintf("%q", ls[i])
+	}
+
+	return fmt.Sprintf("at index %d", i)
+}
+
+// LookAhead creates a parser which runs a given parser without consuming any
+// input.
+func (s *State) LookAhead(p Parser) Parser {
+	return func() (interface{}, error) {
+		ss := *s
+		x, err := p()
+		*s = ss
+
+		return x, err
+	}
+}
+
+// NotFollowedBy creates a parser which succeeds without consuming any input
+// only when a given parser fails.
+func (s *State) NotFollowedBy(p Parser) Parser {
+	return func() (interface{}, error) {
+		ss := *s
+		_, err := p()
+		*s = ss
+
+		if err == nil {
+			return nil, newInvalidCharacterError(s)
+		}
+
+		return nil, s.limitError()
 	}
 }
 
@@ -175,7 +616,7 @@ func (s *State) And(ps ...Parser) Parser {
 		xs := make([]interface{}, 0, len(ps))
 
 		for _, p := range ps {
-			x, err := p()
+			x, err := s.run(p)
 
 			if err != nil {
 				return nil, err
@@ -198,6 +639,12 @@ func (s *State) Lazy(f func() Parser) Parser {
 			p = f()
 		}
 
+		if err := s.enter(); err != nil {
+			return nil, err
+		}
+
+		defer s.leave()
+
 		return p()
 	}
 }
@@ -219,6 +666,8 @@ func (s *State) Exhaust(p Parser) Parser {
 
 		if err != nil {
 			return nil, err
+		} else if err := s.limitError(); err != nil {
+			return nil, err
 		} else if !s.exhausted() {
 			return nil, NewError("source not exhausted", s)
 		}
@@ -254,31 +703,84 @@ func (s *State) Maybe(p Parser) Parser {
 }
 
 // Stringify creates a parser which returns a string converted from a result of
-// a given parser. The result of a given parser must be a rune, a string or a
-// sequence of them in []interface{}.
+// a given parser. The result of a given parser must be a rune, a string, a
+// []rune, a []string, a []byte, a fmt.Stringer, a value convertible by
+// stringifiers added by AddStringifier or a sequence of them in []interface{}.
+// Otherwise, it fails with an error at the position where it starts.
 func (s *State) Stringify(p Parser) Parser {
-	return s.App(func(x interface{}) (interface{}, error) { return stringify(x), nil }, p)
+	return func() (interface{}, error) {
+		ss := *s
+		x, err := p()
+
+		if err != nil {
+			return nil, err
+		}
+
+		str, ok := s.stringify(x)
+
+		if !ok {
+			return nil, newRawError(fmt.Sprintf("cannot stringify result of type %T", x), &ss)
+		}
+
+		return str, nil
+	}
+}
+
+// AddStringifier adds a function to convert results of parsers into strings
+// in Stringify. The function returns false if it cannot convert a value.
+func (s *State) AddStringifier(f func(interface{}) (string, bool)) {
+	s.stringifiers = append(s.stringifiers, f)
 }
 
-func stringify(x interface{}) string {
+func (s *State) stringify(x interface{}) (string, bool) {
+	for _, f := range s.stringifiers {
+		if str, ok := f(x); ok {
+			return str, true
+		}
+	}
+
 	switch x := x.(type) {
 	case nil:
-		return ""
+		return "", true
 	case string:
-		return x
+		return x, true
 	case rune:
-		return string(x)
+		return string(x), true
+	case []rune:
+		return string(x), true
+	case []string:
+		return strings.Join(x, ""), true
+	case []byte:
+		return string(x), true
+	case fmt.Stringer:
+		return x.String(), true
 	case []interface{}:
 		ss := make([]string, 0, len(x))
 
-		for _, s := range x {
-			ss = append(ss, stringify(s))
+		for _, y := range x {
+			str, ok := s.stringify(y)
+
+			if !ok {
+				return "", false
+			}
+
+			ss = append(ss, str)
 		}
 
-		return strings.Join(ss, "")
+		return strings.Join(ss, ""), true
+	}
+
+	return "", false
+}
+
+func equalFold(r, q rune) bool {
+	for rr := unicode.SimpleFold(r); r != q && rr != r; rr = unicode.SimpleFold(rr) {
+		if rr == q {
+			return true
+		}
 	}
 
-	panic("invalid result type for stringify combinator")
+	return r == q
 }
 
 func stringToRuneSet(s string) map[rune]bool {
@@ -2,7 +2,9 @@ package parcom_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/raviqqe/parcom"
 	"github.com/stretchr/testify/assert"
@@ -258,20 +260,69 @@ func TestStringifyWithNil(t *testing.T) {
 	assert.Nil(t, err)
 }
 
-func TestStringifyPanic(t *testing.T) {
+func TestStringifyWithSequences(t *testing.T) {
 	s := parcom.NewState("")
-	assert.Panics(
-		t,
-		func() {
-			_, err := s.Stringify(
-				s.App(func(interface{}) (interface{}, error) { return struct{}{}, nil }, s.None()),
-			)()
 
-			if err != nil {
-				assert.Fail(t, "unreachable")
-			}
-		},
-	)
+	for _, x := range []interface{}{
+		[]rune("foo"),
+		[]string{"f", "oo"},
+		[]byte("foo"),
+		[]interface{}{'f', []rune("o"), []interface{}{"o"}},
+	} {
+		x := x
+		y, err := s.Stringify(
+			s.App(func(interface{}) (interface{}, error) { return x, nil }, s.None()),
+		)()
+
+		assert.Equal(t, "foo", y)
+		assert.Nil(t, err)
+	}
+}
+
+type stringer struct{}
+
+func (stringer) String() string {
+	return "foo"
+}
+
+func TestStringifyWithStringer(t *testing.T) {
+	s := parcom.NewState("")
+	x, err := s.Stringify(
+		s.App(func(interface{}) (interface{}, error) { return stringer{}, nil }, s.None()),
+	)()
+
+	assert.Equal(t, "foo", x)
+	assert.Nil(t, err)
+}
+
+func TestStringifyWithStringifier(t *testing.T) {
+	s := parcom.NewState("42")
+	s.AddStringifier(func(x interface{}) (string, bool) {
+		if x, ok := x.(int64); ok {
+			return fmt.Sprint(x * 2), true
+		}
+
+		return "", false
+	})
+	x, err := s.Stringify(s.And(s.Int(parcom.GoNumberOptions)))()
+
+	assert.Equal(t, "84", x)
+	assert.Nil(t, err)
+}
+
+func TestStringifyError(t *testing.T) {
+	s := parcom.NewState("foo")
+	x, err := s.Prefix(
+		s.Str("foo"),
+		s.Stringify(
+			s.App(func(interface{}) (interface{}, error) { return []interface{}{"foo", 42}, nil }, s.None()),
+		),
+	)()
+
+	assert.Nil(t, x)
+	assert.Error(t, err)
+	assert.Equal(t, "cannot stringify result of type []interface {}", err.Error())
+	assert.Equal(t, 4, err.(parcom.Error).Column())
 }
 
 func TestLazy(t *testing.T) {
@@ -287,3 +338,490 @@ func TestVoid(t *testing.T) {
 	assert.Nil(t, x)
 	assert.Nil(t, err)
 }
+
+func TestSepBy(t *testing.T) {
+	for str, xs := range map[string][]interface{}{
+		"":      {},
+		"a":     {"a"},
+		"a,a,a": {"a", "a", "a"},
+	} {
+		s := parcom.NewState(str)
+		x, err := s.Exhaust(s.SepBy(s.Str("a"), s.Str(",")))()
+
+		assert.Equal(t, xs, x)
+		assert.Nil(t, err)
+	}
+}
+
+func TestSepByErrorWithTrailingSeparator(t *testing.T) {
+	s := parcom.NewState("a,a,b")
+	_, err := s.SepBy(s.Str("a"), s.Str(","))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, err.(parcom.Error).Line())
+	assert.Equal(t, 5, err.(parcom.Error).Column())
+}
+
+func TestSepBy1(t *testing.T) {
+	s := parcom.NewState("a,a")
+	x, err := s.Exhaust(s.SepBy1(s.Str("a"), s.Str(",")))()
+
+	assert.Equal(t, []interface{}{"a", "a"}, x)
+	assert.Nil(t, err)
+}
+
+func TestSepBy1Error(t *testing.T) {
+	s := parcom.NewState("")
+	_, err := s.SepBy1(s.Str("a"), s.Str(","))()
+
+	assert.Error(t, err)
+}
+
+func TestSepEndBy(t *testing.T) {
+	for str, xs := range map[string][]interface{}{
+		"":     {},
+		"a":    {"a"},
+		"a,":   {"a"},
+		"a,a":  {"a", "a"},
+		"a,a,": {"a", "a"},
+	} {
+		s := parcom.NewState(str)
+		x, err := s.Exhaust(s.SepEndBy(s.Str("a"), s.Str(",")))()
+
+		assert.Equal(t, xs, x)
+		assert.Nil(t, err)
+	}
+}
+
+func TestEndBy(t *testing.T) {
+	for str, xs := range map[string][]interface{}{
+		"":     {},
+		"a;":   {"a"},
+		"a;a;": {"a", "a"},
+	} {
+		s := parcom.NewState(str)
+		x, err := s.Exhaust(s.EndBy(s.Str("a"), s.Str(";")))()
+
+		assert.Equal(t, xs, x)
+		assert.Nil(t, err)
+	}
+}
+
+func TestEndByError(t *testing.T) {
+	s := parcom.NewState("a;a")
+	_, err := s.EndBy(s.Str("a"), s.Str(";"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "unexpected end of source", err.Error())
+}
+
+func TestCount(t *testing.T) {
+	s := parcom.NewState("1234")
+	x, err := s.Exhaust(s.Count(4, s.Chars("0123456789")))()
+
+	assert.Equal(t, []interface{}{'1', '2', '3', '4'}, x)
+	assert.Nil(t, err)
+}
+
+func TestCountError(t *testing.T) {
+	s := parcom.NewState("12-")
+	_, err := s.Count(4, s.Chars("0123456789"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "expected at least 4 repetitions, got 2", err.Error())
+	assert.Equal(t, 3, err.(parcom.Error).Column())
+}
+
+func TestCountErrorAtEndOfSource(t *testing.T) {
+	s := parcom.NewState("12")
+	_, err := s.Count(4, s.Chars("0123456789"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "expected at least 4 repetitions, got 2", err.Error())
+	assert.Equal(t, 3, err.(parcom.Error).Column())
+}
+
+func TestCountWithNegativeCount(t *testing.T) {
+	s := parcom.NewState("aaaa")
+	_, err := s.Count(-1, s.Str("a"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalid repetition count -1", err.Error())
+	assert.Equal(t, 1, s.Column())
+}
+
+func TestCountDoesNotParseMore(t *testing.T) {
+	s := parcom.NewState("123")
+	_, err := s.Count(2, s.Chars("0123456789"))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.Column())
+}
+
+func TestRepeat(t *testing.T) {
+	for _, str := range []string{"1", "12", "123"} {
+		s := parcom.NewState(str)
+		x, err := s.Exhaust(s.Repeat(1, 3, s.Chars("0123456789")))()
+
+		assert.Len(t, x, len(str))
+		assert.Nil(t, err)
+	}
+}
+
+func TestRepeatWithoutUpperLimit(t *testing.T) {
+	s := parcom.NewState("12345")
+	x, err := s.Exhaust(s.Repeat(2, -1, s.Chars("0123456789")))()
+
+	assert.Len(t, x, 5)
+	assert.Nil(t, err)
+}
+
+func TestRepeatError(t *testing.T) {
+	for _, str := range []string{"", "1234"} {
+		s := parcom.NewState(str)
+		_, err := s.Exhaust(s.Repeat(1, 3, s.Chars("0123456789")))()
+
+		assert.Error(t, err)
+	}
+}
+
+func TestRepeatWithInvalidRange(t *testing.T) {
+	s := parcom.NewState("aaaa")
+	_, err := s.Repeat(3, 2, s.Str("a"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalid repetition range [3, 2]", err.Error())
+	assert.Equal(t, 1, s.Column())
+}
+
+func TestManyTill(t *testing.T) {
+	s := parcom.NewState("ab*/")
+	x, err := s.Exhaust(s.ManyTill(s.NotChars(""), s.Str("*/")))()
+
+	assert.Equal(t, []interface{}{'a', 'b'}, x)
+	assert.Nil(t, err)
+}
+
+func TestManyTillWithEmptyRepetition(t *testing.T) {
+	s := parcom.NewState("*/")
+	x, err := s.Exhaust(s.ManyTill(s.NotChars(""), s.Str("*/")))()
+
+	assert.Equal(t, []interface{}{}, x)
+	assert.Nil(t, err)
+}
+
+func TestManyTillError(t *testing.T) {
+	s := parcom.NewState("ab")
+	_, err := s.ManyTill(s.Char('a'), s.Str("*/"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, err.(parcom.Error).Column())
+}
+
+func TestManyTillErrorWithEndOfSource(t *testing.T) {
+	s := parcom.NewState("ab*")
+	_, err := s.ManyTill(s.NotChars(""), s.Str("*/"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "unexpected end of source", err.Error())
+	assert.Equal(t, 4, err.(parcom.Error).Column())
+}
+
+func TestManyTillRunsEndParserOncePerElement(t *testing.T) {
+	s := parcom.NewState("ab.")
+	n := 0
+	_, err := s.ManyTill(s.NotChars(""), func() (interface{}, error) {
+		n++
+		return s.Char('.')()
+	})()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestSkipUntil(t *testing.T) {
+	s := parcom.NewState("foo;bar")
+	_, err := s.And(s.SkipUntil(s.Char(';')), s.Str("bar"))()
+
+	assert.Nil(t, err)
+}
+
+func TestSkipUntilError(t *testing.T) {
+	s := parcom.NewState("foo")
+	_, err := s.SkipUntil(s.Char(';'))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "unexpected end of source", err.Error())
+}
+
+func TestLookAhead(t *testing.T) {
+	s := parcom.NewState("foo")
+	x, err := s.LookAhead(s.Str("foo"))()
+
+	assert.Equal(t, "foo", x)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, s.Column())
+}
+
+func TestLookAheadError(t *testing.T) {
+	s := parcom.NewState("fob")
+	_, err := s.LookAhead(s.Str("foo"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, s.Column())
+}
+
+func TestNotFollowedBy(t *testing.T) {
+	s := parcom.NewState("if (")
+	x, err := s.And(s.Str("if"), s.NotFollowedBy(s.Chars("abcdefghijklmnopqrstuvwxyz")))()
+
+	assert.Equal(t, []interface{}{"if", nil}, x)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.Column())
+}
+
+func TestNotFollowedByError(t *testing.T) {
+	s := parcom.NewState("iff")
+	_, err := s.And(s.Str("if"), s.NotFollowedBy(s.Chars("abcdefghijklmnopqrstuvwxyz")))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, err.(parcom.Error).Column())
+}
+
+func TestSatisfy(t *testing.T) {
+	s := parcom.NewState("a")
+	x, err := s.Satisfy(func(r rune) bool { return r == 'a' })()
+
+	assert.Equal(t, 'a', x)
+	assert.Nil(t, err)
+}
+
+func TestSatisfyError(t *testing.T) {
+	for _, str := range []string{"", "b"} {
+		s := parcom.NewState(str)
+		_, err := s.Satisfy(func(r rune) bool { return r == 'a' })()
+
+		assert.Error(t, err)
+	}
+}
+
+func TestInTable(t *testing.T) {
+	s := parcom.NewState("変数x1")
+	x, err := s.Exhaust(
+		s.Many1(s.InTable(unicode.Han, unicode.Latin, unicode.Nd)),
+	)()
+
+	assert.Equal(t, []interface{}{'変', '数', 'x', '1'}, x)
+	assert.Nil(t, err)
+}
+
+func TestInTableError(t *testing.T) {
+	s := parcom.NewState("x")
+	_, err := s.InTable(unicode.Han)()
+
+	assert.Error(t, err)
+}
+
+func TestRange(t *testing.T) {
+	s := parcom.NewState("m")
+	x, err := s.Range('a', 'z')()
+
+	assert.Equal(t, 'm', x)
+	assert.Nil(t, err)
+}
+
+func TestRangeError(t *testing.T) {
+	s := parcom.NewState("M")
+	_, err := s.Range('a', 'z')()
+
+	assert.Equal(t, "expected character in range 'a'-'z'", err.Error())
+}
+
+func TestCharacterClasses(t *testing.T) {
+	s := parcom.NewState("λ٣ Aa!")
+	x, err := s.Exhaust(
+		s.And(s.Letter(), s.Digit(), s.Space(), s.Upper(), s.Lower(), s.AnyChar(), s.EOF()),
+	)()
+
+	assert.Equal(t, []interface{}{'λ', '٣', ' ', 'A', 'a', '!', nil}, x)
+	assert.Nil(t, err)
+}
+
+func TestCharacterClassesError(t *testing.T) {
+	s := parcom.NewState("!")
+
+	for m, p := range map[string]parcom.Parser{
+		"expected letter":            s.Letter(),
+		"expected digit":             s.Digit(),
+		"expected space":             s.Space(),
+		"expected upper case letter": s.Upper(),
+		"expected lower case letter": s.Lower(),
+		"expected end of source":     s.EOF(),
+	} {
+		_, err := p()
+
+		assert.Equal(t, m, err.Error())
+		assert.Equal(t, 1, err.(parcom.Error).Column())
+	}
+}
+
+func TestAnyCharError(t *testing.T) {
+	s := parcom.NewState("")
+	_, err := s.AnyChar()()
+
+	assert.Equal(t, "unexpected end of source", err.Error())
+}
+
+func TestStrFold(t *testing.T) {
+	for _, str := range []string{"select", "SELECT", "Select"} {
+		s := parcom.NewState(str)
+		x, err := s.Exhaust(s.StrFold("select"))()
+
+		assert.Equal(t, str, x)
+		assert.Nil(t, err)
+	}
+}
+
+func TestStrFoldWithNonASCIICharacters(t *testing.T) {
+	s := parcom.NewState("ΣΑΣ")
+	x, err := s.Exhaust(s.StrFold("σας"))()
+
+	assert.Equal(t, "ΣΑΣ", x)
+	assert.Nil(t, err)
+}
+
+func TestStrFoldError(t *testing.T) {
+	s := parcom.NewState("selekt")
+	_, err := s.StrFold("select")()
+
+	assert.Error(t, err)
+	assert.Equal(t, 5, err.(parcom.Error).Column())
+}
+
+func TestKeyword(t *testing.T) {
+	s := parcom.NewState("in x")
+	x, err := s.Keyword("in", s.Letter())()
+
+	assert.Equal(t, "in", x)
+	assert.Nil(t, err)
+}
+
+func TestKeywordError(t *testing.T) {
+	s := parcom.NewState("index")
+	_, err := s.Keyword("in", s.Letter())()
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, err.(parcom.Error).Column())
+}
+
+func testPermute(str string) (interface{}, error) {
+	s := parcom.NewState(str)
+	s.SetTrivia(s.Space())
+	c := func(k string) parcom.Parser {
+		return s.Prefix(s.Str(k+"="), s.Stringify(s.Many1(s.Chars("0123456789abcdefghijklmnopqrstuvwxyz"))))
+	}
+
+	return s.Exhaust(s.Permute(
+		[]parcom.Parser{s.Token(c("name")), s.Token(c("size"))},
+		[]parcom.Parser{s.Token(c("color"))},
+	))()
+}
+
+func TestPermute(t *testing.T) {
+	for str, xs := range map[string][]interface{}{
+		"name=foo;size=42;":           {"foo", "42", nil},
+		"size=42;name=foo;":           {"foo", "42", nil},
+		"color=red;size=42;name=foo;": {"foo", "42", "red"},
+		"size=42;color=red;name=foo;": {"foo", "42", "red"},
+	} {
+		x, err := testPermute(strings.Replace(str, ";", " ", -1))
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, xs, x, str)
+	}
+}
+
+func TestPermuteErrorWithMissingClause(t *testing.T) {
+	_, err := testPermute("name=foo color=red")
+
+	assert.Equal(t, "missing clause at index 1", err.Error())
+}
+
+func TestPermuteErrorWithDuplicateClause(t *testing.T) {
+	for str, c := range map[string]int{
+		"name=foo name=bar size=42": 10,
+		"name=foo size=42 size=42":  18,
+	} {
+		_, err := testPermute(str)
+
+		assert.Error(t, err)
+		assert.Equal(t, "duplicate clause at index", err.Error()[:25])
+		assert.Equal(t, c, err.(parcom.Error).Column())
+	}
+}
+
+func TestPermuteErrorWithLabels(t *testing.T) {
+	for str, m := range map[string]string{
+		"a":   `missing clause "b"`,
+		"aba": `duplicate clause "a"`,
+		"abb": `duplicate clause "b"`,
+	} {
+		s := parcom.NewState(str)
+		_, err := s.Permute([]parcom.Parser{s.Char('a'), s.Char('b')}, nil, "a", "b")()
+
+		assert.Error(t, err)
+		assert.Equal(t, m, err.Error())
+	}
+}
+
+func TestPermuteWithOptionalParserConsumingNoInput(t *testing.T) {
+	s := parcom.NewState("ab")
+	x, err := s.Permute([]parcom.Parser{s.Char('a')}, []parcom.Parser{s.Maybe(s.Char('c'))})()
+
+	assert.Equal(t, []interface{}{'a', nil}, x)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Column())
+}
+
+func TestManyErrorWithParserConsumingNoInput(t *testing.T) {
+	for _, str := range []string{"", "a", "b"} {
+		s := parcom.NewState(str)
+		_, err := s.Many(s.Maybe(s.Char('a')))()
+
+		assert.Error(t, err)
+		assert.Equal(t, "repeated parser succeeded without consuming input", err.Error())
+	}
+}
+
+func TestManyErrorPositionWithParserConsumingNoInput(t *testing.T) {
+	s := parcom.NewState("aab")
+	_, err := s.Many(s.Maybe(s.Char('a')))()
+
+	assert.Equal(t, 1, err.(parcom.Error).Line())
+	assert.Equal(t, 3, err.(parcom.Error).Column())
+}
+
+func TestRepetitionErrorWithParserConsumingNoInput(t *testing.T) {
+	for _, f := range []func(*parcom.State) parcom.Parser{
+		func(s *parcom.State) parcom.Parser { return s.Many(s.None()) },
+		func(s *parcom.State) parcom.Parser { return s.Many1(s.Maybe(s.Char('a'))) },
+		func(s *parcom.State) parcom.Parser { return s.ExhaustiveMany(s.None()) },
+		func(s *parcom.State) parcom.Parser { return s.Repeat(0, -1, s.None()) },
+		func(s *parcom.State) parcom.Parser { return s.SepBy(s.None(), s.None()) },
+		func(s *parcom.State) parcom.Parser { return s.EndBy(s.None(), s.None()) },
+		func(s *parcom.State) parcom.Parser { return s.ManyTill(s.None(), s.Char('b')) },
+	} {
+		s := parcom.NewState("a")
+		_, err := f(s)()
+
+		assert.Error(t, err)
+	}
+}
+
+func TestRepeatWithBoundedParserConsumingNoInput(t *testing.T) {
+	s := parcom.NewState("")
+	x, err := s.Count(3, s.None())()
+
+	assert.Equal(t, []interface{}{nil, nil, nil}, x)
+	assert.Nil(t, err)
+}
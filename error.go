@@ -14,7 +14,7 @@ func NewError(m string, s *State) Error {
 		m = "unexpected end of source"
 	}
 
-	return Error{m, s.Line(), s.Column()}
+	return newRawError(m, s)
 }
 
 func (e Error) Error() string {
@@ -34,3 +34,7 @@ func (e Error) Column() int {
 func newInvalidCharacterError(s *State) Error {
 	return NewError(fmt.Sprintf("invalid character '%c'", s.currentRune()), s)
 }
+
+func newRawError(m string, s *State) Error {
+	return Error{m, s.Line(), s.Column()}
+}
@@ -0,0 +1,269 @@
+package parcom
+
+// OperatorKind is a kind of operators.
+type OperatorKind int
+
+const (
+	// PrefixOperator is a kind of prefix unary operators.
+	PrefixOperator OperatorKind = iota
+	// PostfixOperator is a kind of postfix unary operators.
+	PostfixOperator
+	// InfixLeftOperator is a kind of left-associative binary operators.
+	InfixLeftOperator
+	// InfixRightOperator is a kind of right-associative binary operators.
+	InfixRightOperator
+	// InfixNonAssociativeOperator is a kind of non-associative binary operators.
+	InfixNonAssociativeOperator
+)
+
+// Operator is an operator in an operator table.
+type Operator struct {
+	kind   OperatorKind
+	parser Parser
+	unary  func(interface{}) (interface{}, error)
+	binary func(interface{}, interface{}) (interface{}, error)
+}
+
+// NewPrefixOperator creates a prefix operator.
+func NewPrefixOperator(p Parser, f func(interface{}) (interface{}, error)) Operator {
+	return Operator{PrefixOperator, p, f, nil}
+}
+
+// NewPostfixOperator creates a postfix operator.
+func NewPostfixOperator(p Parser, f func(interface{}) (interface{}, error)) Operator {
+	return Operator{PostfixOperator, p, f, nil}
+}
+
+// NewInfixOperator creates an infix operator of a given kind.
+func NewInfixOperator(k OperatorKind, p Parser, f func(interface{}, interface{}) (interface{}, error)) Operator {
+	return Operator{k, p, nil, f}
+}
+
+// Kind returns a kind of an operator.
+func (o Operator) Kind() OperatorKind {
+	return o.kind
+}
+
+// OperatorTable is a list of operator levels. Levels are ordered from the
+// highest precedence to the lowest.
+type OperatorTable [][]Operator
+
+type precedentOperator struct {
+	Operator
+	precedence int
+}
+
+// Expr creates a parser of expressions composed of terms and operators in a
+// given operator table. It parses operators by precedence climbing and so its
+// recursion depth does not depend on the number of precedence levels.
+func (s *State) Expr(term Parser, t OperatorTable) Parser {
+	ps, os := []precedentOperator{}, []precedentOperator{}
+
+	for i, l := range t {
+		for _, o := range l {
+			o := precedentOperator{o, len(t) - i}
+
+			if o.kind == PrefixOperator {
+				ps = append(ps, o)
+			} else {
+				os = append(os, o)
+			}
+		}
+	}
+
+	e := expressionParser{s, term, ps, os}
+
+	return func() (interface{}, error) {
+		return e.parse(0)
+	}
+}
+
+type expressionParser struct {
+	state                      *State
+	term                       Parser
+	prefixOperators, operators []precedentOperator
+}
+
+func (e expressionParser) parse(min int) (interface{}, error) {
+	if err := e.state.enter(); err != nil {
+		return nil, err
+	}
+
+	defer e.state.leave()
+
+	x, err := e.parseOperand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonAssociative := -1
+
+	for {
+		ss := *e.state
+		o, ok, err := e.parseOperator(e.operators)
+
+		if err != nil {
+			return nil, err
+		} else if !ok || o.precedence < min {
+			*e.state = ss
+			return x, nil
+		}
+
+		switch o.kind {
+		case PostfixOperator:
+			x, err = o.unary(x)
+		case InfixNonAssociativeOperator:
+			if o.precedence == nonAssociative {
+				return nil, NewError("non-associative operator", &ss)
+			}
+
+			nonAssociative = o.precedence
+			x, err = e.parseInfix(o, x, o.precedence+1)
+		case InfixLeftOperator:
+			x, err = e.parseInfix(o, x, o.precedence+1)
+		case InfixRightOperator:
+			x, err = e.parseInfix(o, x, o.precedence)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (e expressionParser) parseOperand() (interface{}, error) {
+	o, ok, err := e.parseOperator(e.prefixOperators)
+
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return e.term()
+	}
+
+	x, err := e.parse(o.precedence)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.unary(x)
+}
+
+func (e expressionParser) parseInfix(o precedentOperator, x interface{}, min int) (interface{}, error) {
+	y, err := e.parse(min)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.binary(x, y)
+}
+
+func (e expressionParser) parseOperator(os []precedentOperator) (precedentOperator, bool, error) {
+	ss := *e.state
+
+	for _, o := range os {
+		if _, err := e.state.run(o.parser); err == nil {
+			return o, true, nil
+		} else if err := e.state.backtrack(&ss); err != nil {
+			return precedentOperator{}, false, err
+		}
+	}
+
+	return precedentOperator{}, false, nil
+}
+
+// Chainl1 creates a parser of more than 0 repetition of a given parser
+// separated by an operator parser and folds their results left-associatively.
+// Results of the operator parser must be functions of the type
+// func(interface{}, interface{}) (interface{}, error).
+func (s *State) Chainl1(p, op Parser) Parser {
+	return func() (interface{}, error) {
+		x, err := p()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			f, ok, err := s.parseChainOperator(op)
+
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				return x, nil
+			}
+
+			y, err := p()
+
+			if err != nil {
+				return nil, err
+			}
+
+			if x, err = f(x, y); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// Chainr1 is the same as Chainl1 but it folds results right-associatively.
+func (s *State) Chainr1(p, op Parser) Parser {
+	return func() (interface{}, error) {
+		x, err := p()
+
+		if err != nil {
+			return nil, err
+		}
+
+		xs := []interface{}{x}
+		fs := []func(interface{}, interface{}) (interface{}, error){}
+
+		for {
+			f, ok, err := s.parseChainOperator(op)
+
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+
+			x, err := p()
+
+			if err != nil {
+				return nil, err
+			}
+
+			xs = append(xs, x)
+			fs = append(fs, f)
+		}
+
+		x = xs[len(xs)-1]
+
+		for i := len(fs) - 1; i >= 0; i-- {
+			if x, err = fs[i](xs[i], x); err != nil {
+				return nil, err
+			}
+		}
+
+		return x, nil
+	}
+}
+
+func (s *State) parseChainOperator(op Parser) (func(interface{}, interface{}) (interface{}, error), bool, error) {
+	ss := *s
+	x, err := s.run(op)
+
+	if err != nil {
+		return nil, false, s.backtrack(&ss)
+	}
+
+	f, ok := x.(func(interface{}, interface{}) (interface{}, error))
+
+	if !ok {
+		return nil, false, NewError("invalid result type for chain operator", &ss)
+	}
+
+	return f, true, nil
+}
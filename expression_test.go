@@ -0,0 +1,150 @@
+package parcom_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func binary(o string) func(interface{}, interface{}) (interface{}, error) {
+	return func(x, y interface{}) (interface{}, error) {
+		return fmt.Sprintf("(%v%v%v)", x, o, y), nil
+	}
+}
+
+func unary(o string, prefix bool) func(interface{}) (interface{}, error) {
+	return func(x interface{}) (interface{}, error) {
+		if prefix {
+			return fmt.Sprintf("(%v%v)", o, x), nil
+		}
+
+		return fmt.Sprintf("(%v%v)", x, o), nil
+	}
+}
+
+func testExpr(str string) (interface{}, error) {
+	s := parcom.NewState(str)
+	o := func(k parcom.OperatorKind, o string) parcom.Operator {
+		return parcom.NewInfixOperator(k, s.Str(o), binary(o))
+	}
+
+	return s.Exhaust(s.Expr(s.Stringify(s.Chars("abcd")), parcom.OperatorTable{
+		{parcom.NewPostfixOperator(s.Str("!"), unary("!", false))},
+		{o(parcom.InfixRightOperator, "^")},
+		{parcom.NewPrefixOperator(s.Str("-"), unary("-", true))},
+		{o(parcom.InfixLeftOperator, "*"), o(parcom.InfixLeftOperator, "/")},
+		{o(parcom.InfixLeftOperator, "+"), o(parcom.InfixLeftOperator, "-")},
+		{o(parcom.InfixNonAssociativeOperator, "==")},
+	}))()
+}
+
+func TestExpr(t *testing.T) {
+	for str, x := range map[string]string{
+		"a":         "a",
+		"a+b":       "(a+b)",
+		"a+b-c":     "((a+b)-c)",
+		"a+b*c":     "(a+(b*c))",
+		"a*b+c":     "((a*b)+c)",
+		"a^b^c":     "(a^(b^c))",
+		"-a*b":      "((-a)*b)",
+		"-a^b":      "(-(a^b))",
+		"--a":       "(-(-a))",
+		"a!^b":      "((a!)^b)",
+		"-a!":       "(-(a!))",
+		"a-b==c*d":  "((a-b)==(c*d))",
+		"a*-b+c":    "((a*(-b))+c)",
+		"a+b*c^d!":  "(a+(b*(c^(d!))))",
+		"a/b/c/d+a": "((((a/b)/c)/d)+a)",
+	} {
+		y, err := testExpr(str)
+
+		assert.Nil(t, err)
+		assert.Equal(t, x, y)
+	}
+}
+
+func TestExprError(t *testing.T) {
+	for _, str := range []string{"", "a+", "-", "a++b", "a==b==c"} {
+		_, err := testExpr(str)
+
+		assert.Error(t, err)
+	}
+}
+
+func TestExprErrorWithNonAssociativeOperator(t *testing.T) {
+	_, err := testExpr("a==b==c")
+
+	assert.Equal(t, "non-associative operator", err.Error())
+	assert.Equal(t, 5, err.(parcom.Error).Column())
+}
+
+func TestExprWithManyLevels(t *testing.T) {
+	s := parcom.NewState("a+a")
+	n := 0
+	tt := parcom.OperatorTable{}
+
+	for i := 0; i < 15; i++ {
+		tt = append(tt, []parcom.Operator{
+			parcom.NewInfixOperator(parcom.InfixLeftOperator, s.Str("+"), binary("+")),
+		})
+	}
+
+	x, err := s.Exhaust(s.Expr(func() (interface{}, error) {
+		n++
+		return s.Str("a")()
+	}, tt))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "(a+a)", x)
+	assert.Equal(t, 2, n)
+}
+
+func testChain(str string, right bool) (interface{}, error) {
+	s := parcom.NewState(str)
+	c := s.Chainl1
+
+	if right {
+		c = s.Chainr1
+	}
+
+	return s.Exhaust(c(
+		s.Stringify(s.Chars("abc")),
+		s.App(func(x interface{}) (interface{}, error) { return binary(x.(string)), nil }, s.Str("-")),
+	))()
+}
+
+func TestChainl1(t *testing.T) {
+	for str, x := range map[string]string{"a": "a", "a-b": "(a-b)", "a-b-c": "((a-b)-c)"} {
+		y, err := testChain(str, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, x, fmt.Sprint(y))
+	}
+}
+
+func TestChainr1(t *testing.T) {
+	for str, x := range map[string]string{"a": "a", "a-b": "(a-b)", "a-b-c": "(a-(b-c))"} {
+		y, err := testChain(str, true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, x, fmt.Sprint(y))
+	}
+}
+
+func TestChainl1Error(t *testing.T) {
+	for _, str := range []string{"", "a-"} {
+		_, err := testChain(str, false)
+
+		assert.Error(t, err)
+	}
+}
+
+func TestChainl1ErrorWithInvalidOperatorResult(t *testing.T) {
+	s := parcom.NewState("a-b")
+	_, err := s.Chainl1(s.Chars("ab"), s.Str("-"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, err.(parcom.Error).Column())
+}
@@ -0,0 +1,100 @@
+package parcom
+
+// SetTrivia sets a parser of a piece of trivia, such as a white space or a
+// comment, which lexeme parsers skip after tokens.
+func (s *State) SetTrivia(p Parser) {
+	s.trivia = p
+}
+
+// Trivia creates a parser which skips trivia set by SetTrivia. It is useful
+// to skip trivia at the beginning of a source. It stops when the trivia parser
+// consumes no input.
+func (s *State) Trivia() Parser {
+	return func() (interface{}, error) {
+		if s.trivia == nil {
+			return nil, nil
+		}
+
+		for {
+			ss := *s
+
+			if _, err := s.run(s.trivia); err != nil {
+				return nil, s.backtrack(&ss)
+			} else if s.sourceIndex == ss.sourceIndex {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// Token creates a parser which runs a given parser and then skips trivia.
+// Positional combinators see a position of the next token after it.
+func (s *State) Token(p Parser) Parser {
+	return s.Suffix(p, s.Trivia())
+}
+
+// Symbol creates a parser which parses a string as a token.
+func (s *State) Symbol(str string) Parser {
+	return s.Token(s.Str(str))
+}
+
+// LineComment creates a parser of a comment which starts with a given string
+// and ends with a new line or the end of a source.
+func (s *State) LineComment(str string) Parser {
+	return s.Void(s.Prefix(s.Str(str), s.SkipUntil(s.Or(s.Char('\n'), s.EOF()))))
+}
+
+// BlockComment creates a parser of a comment which is enclosed by given
+// strings.
+func (s *State) BlockComment(l, r string) Parser {
+	return s.Void(s.Prefix(s.Str(l), s.SkipUntil(s.Str(r))))
+}
+
+// Nested creates a parser of a region enclosed by opening and closing parsers
+// which can be nested, such as nested block comments and balanced brackets.
+// An unterminated region is reported at its outermost opening.
+func (s *State) Nested(l, r Parser) Parser {
+	return func() (interface{}, error) {
+		ss := *s
+
+		if _, err := l(); err != nil {
+			return nil, err
+		}
+
+		for d := 1; d > 0; {
+			ls := *s
+
+			if ok, err := s.succeeds(r); err != nil {
+				return nil, err
+			} else if ok {
+				d--
+			} else if ok, err := s.succeeds(l); err != nil {
+				return nil, err
+			} else if ok {
+				if err := s.checkProgress(&ls); err != nil {
+					return nil, err
+				}
+
+				d++
+			} else if s.exhausted() {
+				return nil, NewError("unterminated nested region", &ss)
+			} else {
+				s.readRune()
+			}
+		}
+
+		return nil, nil
+	}
+}
+
+// succeeds runs a parser and backtracks if it fails. It returns an error only
+// when the failure is not recoverable.
+func (s *State) succeeds(p Parser) (bool, error) {
+	ss := *s
+
+	if _, err := s.run(p); err != nil {
+		return false, s.backtrack(&ss)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,111 @@
+package parcom_test
+
+import (
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTriviaState(str string) *parcom.PositionalState {
+	s := parcom.NewPositionalState(str)
+	s.SetTrivia(s.Or(s.Space(), s.LineComment("//"), s.BlockComment("/*", "*/")))
+	return s
+}
+
+func TestToken(t *testing.T) {
+	s := newTriviaState("foo /* comment */ // comment\n bar")
+	x, err := s.Exhaust(s.And(s.Token(s.Str("foo")), s.Symbol("bar")))()
+
+	assert.Equal(t, []interface{}{"foo", "bar"}, x)
+	assert.Nil(t, err)
+}
+
+func TestTokenWithoutTrivia(t *testing.T) {
+	s := parcom.NewState("foo bar")
+	_, err := s.Exhaust(s.And(s.Symbol("foo"), s.Symbol("bar")))()
+
+	assert.Error(t, err)
+}
+
+func TestTrivia(t *testing.T) {
+	s := newTriviaState(" // comment\nfoo")
+	x, err := s.Exhaust(s.Prefix(s.Trivia(), s.Symbol("foo")))()
+
+	assert.Equal(t, "foo", x)
+	assert.Nil(t, err)
+}
+
+func TestTriviaConsumingNoInput(t *testing.T) {
+	s := parcom.NewState("foo  bar")
+	s.SetTrivia(s.Many(s.Space()))
+	x, err := s.Exhaust(s.And(s.Symbol("foo"), s.Symbol("bar")))()
+
+	assert.Equal(t, []interface{}{"foo", "bar"}, x)
+	assert.Nil(t, err)
+}
+
+func TestTriviaErrorWithUnterminatedComment(t *testing.T) {
+	s := newTriviaState("foo /* comment")
+	_, err := s.Exhaust(s.Symbol("foo"))()
+
+	assert.Error(t, err)
+}
+
+func TestTokenWithPositionalState(t *testing.T) {
+	s := newTriviaState("foo /* comment */\n  bar // comment\n  bar")
+	_, err := s.Exhaust(s.WithBlock(s.Symbol("foo"), s.Symbol("bar")))()
+
+	assert.Nil(t, err)
+}
+
+func TestTokenWithPositionalStateError(t *testing.T) {
+	s := newTriviaState("foo /* comment */\n foo")
+	_, err := s.Exhaust(s.Block(s.Symbol("foo")))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, err.(parcom.Error).Line())
+	assert.Equal(t, 2, err.(parcom.Error).Column())
+}
+
+func TestNested(t *testing.T) {
+	for _, str := range []string{"/**/", "/* foo */", "/* /* foo */ */", "/* /**/ /* /**/ */ */"} {
+		s := parcom.NewState(str)
+		_, err := s.Exhaust(s.Nested(s.Str("/*"), s.Str("*/")))()
+
+		assert.Nil(t, err)
+	}
+}
+
+func TestNestedWithBrackets(t *testing.T) {
+	s := parcom.NewState("(a (b) ((c)))d")
+	_, err := s.Exhaust(s.And(s.Nested(s.Char('('), s.Char(')')), s.Char('d')))()
+
+	assert.Nil(t, err)
+}
+
+func TestNestedError(t *testing.T) {
+	s := parcom.NewState("foo /* /* */")
+	_, err := s.Prefix(s.Str("foo "), s.Nested(s.Str("/*"), s.Str("*/")))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "unterminated nested region", err.Error())
+	assert.Equal(t, 1, err.(parcom.Error).Line())
+	assert.Equal(t, 5, err.(parcom.Error).Column())
+}
+
+func TestNestedWithOpeningParserConsumingNoInput(t *testing.T) {
+	s := parcom.NewState("{x")
+	_, err := s.Nested(s.Maybe(s.Str("{")), s.Str("}"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, "repeated parser succeeded without consuming input", err.Error())
+}
+
+func TestNestedAsTrivia(t *testing.T) {
+	s := parcom.NewState("foo /* /* */ */ bar")
+	s.SetTrivia(s.Or(s.Space(), s.Nested(s.Str("/*"), s.Str("*/"))))
+	_, err := s.Exhaust(s.And(s.Symbol("foo"), s.Symbol("bar")))()
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,186 @@
+package parcom
+
+import "context"
+
+// contextCheckInterval is the number of parser invocations between checks of
+// contexts. It must be a power of 2.
+const contextCheckInterval = 1024
+
+// Limits is a set of limits on parsing to protect parsers from malicious
+// inputs. Zero values mean no limits.
+type Limits struct {
+	// Depth is the maximum depth of recursion through Lazy and Expr.
+	Depth int
+	// Steps is the maximum number of parser invocations.
+	Steps int
+	// Backtracks is the maximum number of backtracks.
+	Backtracks int
+}
+
+// LimitError is an error of exceeded limits. No combinator recovers from it.
+type LimitError struct {
+	err Error
+}
+
+func (e LimitError) Error() string {
+	return e.err.Error()
+}
+
+// Line returns a line number.
+func (e LimitError) Line() int {
+	return e.err.Line()
+}
+
+// Column returns a column number.
+func (e LimitError) Column() int {
+	return e.err.Column()
+}
+
+// CancelError is an error of parsing cancelled by a context. No combinator
+// recovers from it.
+type CancelError struct {
+	err   Error
+	cause error
+}
+
+func (e CancelError) Error() string {
+	return e.err.Error()
+}
+
+// Line returns a line number.
+func (e CancelError) Line() int {
+	return e.err.Line()
+}
+
+// Column returns a column number.
+func (e CancelError) Column() int {
+	return e.err.Column()
+}
+
+// Unwrap returns an error of a context.
+func (e CancelError) Unwrap() error {
+	return e.cause
+}
+
+// Limits, contexts and counters are shared by snapshots of parser states so
+// that backtracking does not reset them.
+type limitState struct {
+	Limits
+	context                  context.Context
+	depth, steps, backtracks int
+	err                      error
+}
+
+// SetLimits sets limits on parsing.
+func (s *State) SetLimits(l Limits) {
+	s.limitState().Limits = l
+}
+
+// SetContext binds a context to a parser state. Parsers fail with CancelError
+// when the context is done.
+func (s *State) SetContext(c context.Context) {
+	s.limitState().context = c
+}
+
+func (s *State) limitState() *limitState {
+	if s.limits == nil {
+		s.limits = &limitState{}
+	}
+
+	return s.limits
+}
+
+func (s *State) run(p Parser) (interface{}, error) {
+	if err := s.step(); err != nil {
+		return nil, err
+	}
+
+	return p()
+}
+
+func (s *State) step() error {
+	l := s.limits
+
+	if l == nil {
+		return nil
+	} else if l.err != nil {
+		return l.err
+	}
+
+	l.steps++
+
+	if l.Steps > 0 && l.steps > l.Steps {
+		return s.exceedLimit("step limit exceeded")
+	} else if l.context != nil && l.steps&(contextCheckInterval-1) == 1 {
+		if err := l.context.Err(); err != nil {
+			l.err = CancelError{newRawError("parsing cancelled: "+err.Error(), s), err}
+			return l.err
+		}
+	}
+
+	return nil
+}
+
+func (s *State) enter() error {
+	if err := s.step(); err != nil {
+		return err
+	} else if s.limits == nil {
+		return nil
+	}
+
+	s.limits.depth++
+
+	if s.limits.Depth > 0 && s.limits.depth > s.limits.Depth {
+		return s.exceedLimit("recursion depth limit exceeded")
+	}
+
+	return nil
+}
+
+func (s *State) leave() {
+	if s.limits != nil {
+		s.limits.depth--
+	}
+}
+
+// backtrack restores a parser state after a failure of a parser. It returns a
+// non-nil error when the failure is not recoverable.
+func (s *State) backtrack(ss *State) error {
+	l := s.limits
+
+	if l != nil && l.err != nil {
+		return l.err
+	}
+
+	*s = *ss
+	s.trace(TraceBacktrack, "", "", "")
+
+	if s.profiler != nil {
+		s.profiler.discard(s.profiled)
+	}
+
+	if l == nil {
+		return nil
+	}
+
+	l.backtracks++
+
+	if l.Backtracks > 0 && l.backtracks > l.Backtracks {
+		return s.exceedLimit("backtrack limit exceeded")
+	}
+
+	return nil
+}
+
+func (s *State) limitError() error {
+	if s.limits == nil {
+		return nil
+	}
+
+	return s.limits.err
+}
+
+func (s *State) exceedLimit(m string) error {
+	s.limits.err = LimitError{newRawError(m, s)}
+	return s.limits.err
+}
@@ -0,0 +1,162 @@
+package parcom_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func parentheses(s *parcom.State) parcom.Parser {
+	var p parcom.Parser
+	p = s.Or(s.Wrap(s.Char('('), s.Lazy(func() parcom.Parser { return p }), s.Char(')')), s.Char('x'))
+	return p
+}
+
+func TestLimitsWithDepth(t *testing.T) {
+	s := parcom.NewState("((x))")
+	s.SetLimits(parcom.Limits{Depth: 2})
+	_, err := s.Exhaust(parentheses(s))()
+
+	assert.Nil(t, err)
+}
+
+func TestLimitsErrorWithDepth(t *testing.T) {
+	s := parcom.NewState(strings.Repeat("(", 100000))
+	s.SetLimits(parcom.Limits{Depth: 100})
+	_, err := s.Exhaust(parentheses(s))()
+
+	assert.IsType(t, parcom.LimitError{}, err)
+	assert.Equal(t, "recursion depth limit exceeded", err.Error())
+	assert.Equal(t, 102, err.(parcom.LimitError).Column())
+}
+
+func TestLimitsErrorWithSteps(t *testing.T) {
+	s := parcom.NewState(strings.Repeat("a", 1000))
+	s.SetLimits(parcom.Limits{Steps: 100})
+	_, err := s.Exhaust(s.Many(s.Char('a')))()
+
+	assert.IsType(t, parcom.LimitError{}, err)
+	assert.Equal(t, "step limit exceeded", err.Error())
+}
+
+func TestLimitsErrorWithBacktracks(t *testing.T) {
+	s := parcom.NewState(strings.Repeat("ab", 100))
+	s.SetLimits(parcom.Limits{Backtracks: 10})
+	_, err := s.Exhaust(s.Many(s.Or(s.Char('b'), s.Char('a'))))()
+
+	assert.IsType(t, parcom.LimitError{}, err)
+	assert.Equal(t, "backtrack limit exceeded", err.Error())
+}
+
+func TestLimitsErrorNotRecovered(t *testing.T) {
+	for _, f := range []func(*parcom.State, parcom.Parser) parcom.Parser{
+		func(s *parcom.State, p parcom.Parser) parcom.Parser { return s.Or(p, s.None()) },
+		func(s *parcom.State, p parcom.Parser) parcom.Parser { return s.Many(p) },
+		func(s *parcom.State, p parcom.Parser) parcom.Parser { return s.Maybe(p) },
+		func(s *parcom.State, p parcom.Parser) parcom.Parser { return s.SepBy(p, s.Char(',')) },
+		func(s *parcom.State, p parcom.Parser) parcom.Parser { return s.NotFollowedBy(p) },
+		func(s *parcom.State, p parcom.Parser) parcom.Parser { return s.Nested(s.Char('('), p) },
+	} {
+		s := parcom.NewState(strings.Repeat("(", 100))
+		s.SetLimits(parcom.Limits{Depth: 10})
+		_, err := f(s, parentheses(s))()
+
+		assert.IsType(t, parcom.LimitError{}, err)
+	}
+}
+
+func TestLimitsWithStepsInNested(t *testing.T) {
+	s := parcom.NewState("/*" + strings.Repeat(" ", 1000))
+	s.SetLimits(parcom.Limits{Steps: 50})
+	_, err := s.Nested(s.Str("/*"), s.Str("*/"))()
+
+	assert.IsType(t, parcom.LimitError{}, err)
+	assert.True(t, s.Column() < 50)
+}
+
+func TestLimitsWithBacktracksInLiterals(t *testing.T) {
+	s := parcom.NewState(strings.Repeat("42,", 20) + `"\ud83d\ude00"`)
+	s.SetLimits(parcom.Limits{Backtracks: 1})
+	_, err := s.Exhaust(s.And(
+		s.SepEndBy(s.Int(parcom.GoNumberOptions), s.Char(',')),
+		s.QuotedString(parcom.JSONStringOptions),
+	))()
+
+	assert.Nil(t, err)
+}
+
+func TestLimitsErrorWithExpr(t *testing.T) {
+	s := parcom.NewState(strings.Repeat("-", 1000) + "x")
+	s.SetLimits(parcom.Limits{Depth: 100})
+	_, err := s.Exhaust(s.Expr(s.Char('x'), parcom.OperatorTable{
+		{parcom.NewPrefixOperator(s.Char('-'), func(x interface{}) (interface{}, error) { return x, nil })},
+	}))()
+
+	assert.IsType(t, parcom.LimitError{}, err)
+}
+
+func TestLimitsWithSteps(t *testing.T) {
+	s := parcom.NewState("aaa")
+	s.SetLimits(parcom.Limits{Steps: 3})
+	_, err := s.Many(s.Char('a'))()
+
+	assert.IsType(t, parcom.LimitError{}, err)
+
+	s = parcom.NewState("aaa")
+	s.SetLimits(parcom.Limits{Steps: 5})
+	_, err = s.Many(s.Char('a'))()
+
+	assert.Nil(t, err)
+}
+
+func TestSetContext(t *testing.T) {
+	s := parcom.NewState(strings.Repeat("a", 10000))
+	s.SetContext(context.Background())
+	_, err := s.Exhaust(s.Many(s.Char('a')))()
+
+	assert.Nil(t, err)
+}
+
+func TestSetContextWithCancel(t *testing.T) {
+	s := parcom.NewState(strings.Repeat("a", 10000))
+	c, cancel := context.WithCancel(context.Background())
+	s.SetContext(c)
+	n := 0
+	_, err := s.Exhaust(s.Many(s.Or(s.Char('b'), func() (interface{}, error) {
+		if n++; n == 5000 {
+			cancel()
+		}
+
+		return s.Char('a')()
+	})))()
+
+	assert.IsType(t, parcom.CancelError{}, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, "parsing cancelled: context canceled", err.Error())
+	assert.True(t, err.(parcom.CancelError).Column() > 5000)
+	assert.True(t, err.(parcom.CancelError).Column() < 6000)
+}
+
+func TestSetContextWithDeadline(t *testing.T) {
+	s := parcom.NewState("a")
+	c, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	s.SetContext(c)
+	_, err := s.Maybe(s.Char('a'))()
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestSetContextWithPositionalState(t *testing.T) {
+	s := parcom.NewPositionalState(strings.Repeat("a\n", 10))
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.SetContext(c)
+	_, err := s.Block(s.Str("a\n"))()
+
+	assert.IsType(t, parcom.CancelError{}, err)
+}
@@ -0,0 +1,255 @@
+package parcom
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NumberOptions is a syntax of numeric literals.
+type NumberOptions struct {
+	// MinusSign and PlusSign allow leading minus and plus signs respectively.
+	MinusSign, PlusSign bool
+	// HexPrefix, OctalPrefix and BinaryPrefix are case-insensitive prefixes of
+	// integer literals in those bases. Empty prefixes are disabled.
+	HexPrefix, OctalPrefix, BinaryPrefix string
+	// LegacyOctal makes decimal integer literals with leading zeros octal.
+	LegacyOctal bool
+	// LeadingZeros allows leading zeros in decimal literals.
+	LeadingZeros bool
+	// Separator is a character allowed between digits and after prefixes. 0
+	// disables it.
+	Separator rune
+	// BitSize is a bit size of results used for range checks. 0 means 64.
+	BitSize int
+}
+
+var (
+	// GoNumberOptions is a syntax of numeric literals in Go.
+	GoNumberOptions = NumberOptions{
+		MinusSign:    true,
+		PlusSign:     true,
+		HexPrefix:    "0x",
+		OctalPrefix:  "0o",
+		BinaryPrefix: "0b",
+		LegacyOctal:  true,
+		LeadingZeros: true,
+		Separator:    '_',
+	}
+	// JSONNumberOptions is a syntax of numeric literals in JSON.
+	JSONNumberOptions = NumberOptions{MinusSign: true}
+	// CNumberOptions is a syntax of numeric literals in C.
+	CNumberOptions = NumberOptions{
+		MinusSign:    true,
+		PlusSign:     true,
+		HexPrefix:    "0x",
+		BinaryPrefix: "0b",
+		LegacyOctal:  true,
+		LeadingZeros: true,
+	}
+)
+
+type numberMode int
+
+const (
+	integerNumberMode numberMode = iota
+	floatNumberMode
+	anyNumberMode
+)
+
+type numberLiteral struct {
+	sign                       string
+	base                       int
+	digits, fraction, exponent string
+	float                      bool
+}
+
+// Int creates a parser of an integer literal. Its result is int64.
+func (s *State) Int(o NumberOptions) Parser {
+	return s.number(o, integerNumberMode, true)
+}
+
+// Uint creates a parser of an unsigned integer literal. Its result is uint64.
+func (s *State) Uint(o NumberOptions) Parser {
+	return s.number(o, integerNumberMode, false)
+}
+
+// Float creates a parser of a decimal floating-point number literal. Its
+// result is float64. Fractions and exponents are optional.
+func (s *State) Float(o NumberOptions) Parser {
+	return s.number(o, floatNumberMode, true)
+}
+
+// Number creates a parser of an integer or floating-point number literal.
+// Its result is int64 for integers or float64 otherwise.
+func (s *State) Number(o NumberOptions) Parser {
+	return s.number(o, anyNumberMode, true)
+}
+
+func (s *State) number(o NumberOptions, m numberMode, signed bool) Parser {
+	if o.BitSize == 0 {
+		o.BitSize = 64
+	}
+
+	return func() (interface{}, error) {
+		ss := *s
+		l, err := s.scanNumber(o, m, signed)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var x interface{}
+
+		if l.float {
+			x, err = strconv.ParseFloat(
+				l.sign+l.digits+"."+l.fraction+"e"+l.exponent,
+				o.BitSize,
+			)
+		} else if signed {
+			x, err = strconv.ParseInt(l.sign+l.digits, l.base, o.BitSize)
+		} else {
+			x, err = strconv.ParseUint(l.digits, l.base, o.BitSize)
+		}
+
+		if err == nil {
+			return x, nil
+		} else if err.(*strconv.NumError).Err == strconv.ErrRange {
+			return nil, NewError("number literal out of range", &ss)
+		}
+
+		return nil, NewError("invalid number literal", &ss)
+	}
+}
+
+func (s *State) scanNumber(o NumberOptions, m numberMode, signed bool) (numberLiteral, error) {
+	l := numberLiteral{"", 10, "", "0", "0", m == floatNumberMode}
+
+	if r := s.currentRune(); signed && (o.MinusSign && r == '-' || o.PlusSign && r == '+') {
+		l.sign = string(r)
+		s.readRune()
+	}
+
+	if m != floatNumberMode {
+		for i, p := range []string{o.HexPrefix, o.OctalPrefix, o.BinaryPrefix} {
+			if n := utf8.RuneCountInString(p); n > 0 && strings.EqualFold(s.PeekN(n), p) {
+				for j := 0; j < n; j++ {
+					s.readRune()
+				}
+
+				l.base = []int{16, 8, 2}[i]
+				ds, err := s.scanDigits(o, l.base, true)
+				l.digits = ds
+
+				return l, err
+			}
+		}
+	}
+
+	ss := *s
+	ds, err := s.scanDigits(o, 10, false)
+
+	if err != nil {
+		return l, err
+	} else if !o.LeadingZeros && len(ds) > 1 && ds[0] == '0' {
+		*s = ss
+		s.readRune()
+		ds = "0"
+	}
+
+	l.digits = ds
+
+	if m == integerNumberMode {
+		return s.legacyOctal(o, l, &ss)
+	}
+
+	if s.currentRune() == '.' {
+		ss := *s
+		s.readRune()
+
+		if !isDigit(s.currentRune(), 10) {
+			*s = ss
+		} else if l.fraction, err = s.scanDigits(o, 10, false); err != nil {
+			return l, err
+		} else {
+			l.float = true
+		}
+	}
+
+	if s.currentRune() == 'e' || s.currentRune() == 'E' {
+		ss := *s
+		s.readRune()
+		sign := ""
+
+		if s.currentRune() == '-' || s.currentRune() == '+' {
+			sign = string(s.currentRune())
+			s.readRune()
+		}
+
+		if !isDigit(s.currentRune(), 10) {
+			*s = ss
+		} else if l.exponent, err = s.scanDigits(o, 10, false); err != nil {
+			return l, err
+		} else {
+			l.exponent = sign + l.exponent
+			l.float = true
+		}
+	}
+
+	if l.float {
+		return l, nil
+	}
+
+	return s.legacyOctal(o, l, &ss)
+}
+
+func (s *State) legacyOctal(o NumberOptions, l numberLiteral, ss *State) (numberLiteral, error) {
+	if !o.LegacyOctal || len(l.digits) < 2 || l.digits[0] != '0' {
+		return l, nil
+	}
+
+	for _, r := range l.digits {
+		if !isDigit(r, 8) {
+			return l, NewError("invalid octal literal", ss)
+		}
+	}
+
+	l.base = 8
+
+	return l, nil
+}
+
+// scanDigits scans digits in a given base. A separator is allowed before the
+// first digit if they are prefixed.
+func (s *State) scanDigits(o NumberOptions, b int, prefixed bool) (string, error) {
+	rs := []rune{}
+
+	for {
+		if isDigit(s.currentRune(), b) {
+			rs = append(rs, s.currentRune())
+			s.readRune()
+			continue
+		} else if o.Separator == 0 || s.currentRune() != o.Separator || len(rs) == 0 && !prefixed {
+			break
+		}
+
+		ss := *s
+		s.readRune()
+
+		if !isDigit(s.currentRune(), b) {
+			return "", NewError("invalid digit separator", &ss)
+		}
+	}
+
+	if len(rs) == 0 {
+		return "", NewError("expected digit", s)
+	}
+
+	return string(rs), nil
+}
+
+func isDigit(r rune, b int) bool {
+	i := strings.IndexRune("0123456789abcdef", unicode.ToLower(r))
+	return i >= 0 && i < b
+}
@@ -0,0 +1,168 @@
+package parcom_test
+
+import (
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInt(t *testing.T) {
+	for str, x := range map[string]int64{
+		"0":                    0,
+		"42":                   42,
+		"-42":                  -42,
+		"+42":                  42,
+		"1_000_000":            1000000,
+		"0xFF":                 255,
+		"0x_1f":                31,
+		"0b_1_0":               2,
+		"0XfF":                 255,
+		"0o17":                 15,
+		"017":                  15,
+		"0b1010":               10,
+		"-0x10":                -16,
+		"9223372036854775807":  9223372036854775807,
+		"-9223372036854775808": -9223372036854775808,
+	} {
+		s := parcom.NewState(str)
+		y, err := s.Exhaust(s.Int(parcom.GoNumberOptions))()
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, x, y, str)
+	}
+}
+
+func TestIntError(t *testing.T) {
+	for str, m := range map[string]string{
+		"":                    "unexpected end of source",
+		"x":                   "expected digit",
+		"0x":                  "unexpected end of source",
+		"0xg":                 "expected digit",
+		"1__0":                "invalid digit separator",
+		"1_":                  "invalid digit separator",
+		"0x_":                 "invalid digit separator",
+		"0x__1":               "invalid digit separator",
+		"_1":                  "expected digit",
+		"09":                  "invalid octal literal",
+		"9223372036854775808": "number literal out of range",
+	} {
+		s := parcom.NewState(str)
+		_, err := s.Exhaust(s.Int(parcom.GoNumberOptions))()
+
+		assert.Equal(t, m, err.Error(), str)
+	}
+}
+
+func TestIntErrorPosition(t *testing.T) {
+	s := parcom.NewState("x = 99999999999999999999")
+	_, err := s.Prefix(s.Str("x = "), s.Int(parcom.GoNumberOptions))()
+
+	assert.Equal(t, "number literal out of range", err.Error())
+	assert.Equal(t, 5, err.(parcom.Error).Column())
+}
+
+func TestIntWithBitSize(t *testing.T) {
+	o := parcom.GoNumberOptions
+	o.BitSize = 8
+
+	for str, ok := range map[string]bool{"127": true, "128": false, "-128": true} {
+		s := parcom.NewState(str)
+		_, err := s.Exhaust(s.Int(o))()
+
+		assert.Equal(t, ok, err == nil, str)
+	}
+}
+
+func TestIntWithJSONOptions(t *testing.T) {
+	for str, ok := range map[string]bool{"0": true, "-1": true, "+1": false, "01": false, "0x1": false, "1_0": false} {
+		s := parcom.NewState(str)
+		_, err := s.Exhaust(s.Int(parcom.JSONNumberOptions))()
+
+		assert.Equal(t, ok, err == nil, str)
+	}
+}
+
+func TestUint(t *testing.T) {
+	s := parcom.NewState("18446744073709551615")
+	x, err := s.Exhaust(s.Uint(parcom.CNumberOptions))()
+
+	assert.Equal(t, uint64(18446744073709551615), x)
+	assert.Nil(t, err)
+}
+
+func TestUintError(t *testing.T) {
+	s := parcom.NewState("-1")
+	_, err := s.Uint(parcom.CNumberOptions)()
+
+	assert.Error(t, err)
+}
+
+func TestFloat(t *testing.T) {
+	for str, x := range map[string]float64{
+		"1":         1,
+		"-1.5":      -1.5,
+		"3.14":      3.14,
+		"1e3":       1000,
+		"1E-3":      0.001,
+		"2.5e+2":    250,
+		"1_000.5":   1000.5,
+		"0.000_001": 0.000001,
+	} {
+		s := parcom.NewState(str)
+		y, err := s.Exhaust(s.Float(parcom.GoNumberOptions))()
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, x, y, str)
+	}
+}
+
+func TestFloatWithoutFraction(t *testing.T) {
+	s := parcom.NewState("1.x")
+	x, err := s.Float(parcom.JSONNumberOptions)()
+
+	assert.Equal(t, 1.0, x)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Column())
+}
+
+func TestFloatError(t *testing.T) {
+	s := parcom.NewState("1e400")
+	_, err := s.Float(parcom.JSONNumberOptions)()
+
+	assert.Equal(t, "number literal out of range", err.Error())
+	assert.Equal(t, 1, err.(parcom.Error).Column())
+}
+
+func TestNumber(t *testing.T) {
+	for str, x := range map[string]interface{}{
+		"42":     int64(42),
+		"0755":   int64(493),
+		"0x10":   int64(16),
+		"4.2":    4.2,
+		"0755.5": 755.5,
+		"-1e2":   -100.0,
+	} {
+		s := parcom.NewState(str)
+		y, err := s.Exhaust(s.Number(parcom.GoNumberOptions))()
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, x, y, str)
+	}
+}
+
+func TestNumberWithJSONOptions(t *testing.T) {
+	for str, x := range map[string]interface{}{
+		"0":       int64(0),
+		"-0.5":    -0.5,
+		"1.5e10":  1.5e10,
+		"123e-2":  1.23,
+		"1000000": int64(1000000),
+	} {
+		s := parcom.NewState(str)
+		y, err := s.Exhaust(s.Number(parcom.JSONNumberOptions))()
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, x, y, str)
+	}
+}
@@ -7,12 +7,11 @@ type position struct {
 // PositionalState is a position-aware parser state.
 type PositionalState struct {
 	State
-	position position
 }
 
 // NewPositionalState creates a parser state.
 func NewPositionalState(s string) *PositionalState {
-	return &PositionalState{*NewState(s), position{-1, -1}}
+	return &PositionalState{*NewState(s)}
 }
 
 // WithPosition creates a parser saving a current position.
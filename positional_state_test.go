@@ -241,3 +241,79 @@ func TestPositionalStateSameColumnError(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestPositionalStateLookAhead(t *testing.T) {
+	s := newState("foo\nfoo")
+	_, err := s.WithPosition(
+		s.And(
+			s.trimRight(s.Str("foo")),
+			s.LookAhead(s.WithPosition(s.Str("foo"))),
+			s.SameColumn(s.Str("foo")),
+		),
+	)()
+
+	assert.Nil(t, err)
+}
+
+func TestPositionalStateNotFollowedBy(t *testing.T) {
+	s := newState("foo\n bar")
+	_, err := s.WithPosition(
+		s.And(
+			s.trimRight(s.Str("foo")),
+			s.NotFollowedBy(s.SameColumn(s.Str("bar"))),
+			s.Indent(s.Str("bar")),
+		),
+	)()
+
+	assert.Nil(t, err)
+}
+
+func TestPositionalStateBlockErrorWithParserConsumingNoInput(t *testing.T) {
+	s := newState("foo")
+	_, err := s.Block(s.Maybe(s.Str("bar")))()
+
+	assert.Error(t, err)
+}
+
+func TestPositionalStateCheckpoint(t *testing.T) {
+	s := newState("foo\n foo")
+	c := s.Checkpoint()
+	_, err := s.WithPosition(func() (interface{}, error) {
+		_, err := s.trimRight(s.Str("foo"))()
+
+		if err != nil {
+			return nil, err
+		}
+
+		s.Rewind(c)
+
+		return nil, nil
+	})()
+
+	assert.Nil(t, err)
+
+	_, err = s.Indent(s.Str("foo"))()
+
+	assert.Nil(t, err)
+}
+
+func TestPositionalStateOrRestoresPosition(t *testing.T) {
+	s := newState("foo\nfoo")
+	_, err := s.WithPosition(
+		s.And(
+			s.trimRight(s.Str("foo")),
+			s.Or(
+				func() (interface{}, error) {
+					if _, err := s.WithPosition(s.None())(); err != nil {
+						return nil, err
+					}
+
+					return s.Str("bar")()
+				},
+				s.SameColumn(s.Str("foo")),
+			),
+		),
+	)()
+
+	assert.Nil(t, err)
+}
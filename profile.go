@@ -0,0 +1,341 @@
+package parcom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// RuleProfile is a profile of a named parser.
+type RuleProfile struct {
+	Name                       string
+	Calls, Successes, Failures int
+	// Time is cumulative time spent in a parser excluding time of its
+	// recursive calls.
+	Time time.Duration
+	// Consumed is the number of characters consumed by successful calls.
+	Consumed int
+	// WastedTime and Wasted are time spent in and the number of characters
+	// consumed by calls which fail or whose results are discarded on
+	// backtracking.
+	WastedTime time.Duration
+	Wasted     int
+}
+
+type profileFrame struct {
+	name     string
+	start    time.Time
+	children time.Duration
+}
+
+type profileSample struct {
+	stack            []string
+	calls, time      int64
+	consumed, wasted int64
+}
+
+// profileCall is a successful call which can be discarded on backtracking.
+type profileCall struct {
+	rule     *RuleProfile
+	sample   *profileSample
+	time     time.Duration
+	consumed int
+}
+
+// Profiler is a profiler of named parsers.
+type Profiler struct {
+	rules   map[string]*RuleProfile
+	samples map[string]*profileSample
+	stack   []profileFrame
+	calls   []profileCall
+}
+
+// NewProfiler creates a profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{map[string]*RuleProfile{}, map[string]*profileSample{}, nil, nil}
+}
+
+// SetProfiler enables profiling of named parsers.
+func (s *State) SetProfiler(p *Profiler) {
+	s.profiler = p
+}
+
+func (p *Profiler) enter(n string) {
+	p.stack = append(p.stack, profileFrame{n, time.Now(), 0})
+}
+
+func (p *Profiler) leave(n int, ok bool) {
+	f := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	d := time.Since(f.start)
+
+	if len(p.stack) > 0 {
+		p.stack[len(p.stack)-1].children += d
+	}
+
+	r := p.rules[f.name]
+
+	if r == nil {
+		r = &RuleProfile{Name: f.name}
+		p.rules[f.name] = r
+	}
+
+	r.Calls++
+
+	if !p.recursive(f.name) {
+		r.Time += d
+	}
+
+	ss := []string{f.name}
+
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		ss = append(ss, p.stack[i].name)
+	}
+
+	k := strings.Join(ss, "\x00")
+	x := p.samples[k]
+
+	if x == nil {
+		x = &profileSample{stack: ss}
+		p.samples[k] = x
+	}
+
+	x.calls++
+	x.time += int64(d - f.children)
+
+	if ok {
+		r.Successes++
+		r.Consumed += n
+		x.consumed += int64(n)
+		p.calls = append(p.calls, profileCall{r, x, d, n})
+	} else {
+		r.Failures++
+		r.WastedTime += d
+		r.Wasted += n
+		x.wasted += int64(n)
+	}
+}
+
+// discard counts successful calls after the first i ones as wasted ones since
+// their results are discarded on backtracking.
+func (p *Profiler) discard(i int) {
+	if i >= len(p.calls) {
+		return
+	}
+
+	for _, c := range p.calls[i:] {
+		c.rule.Consumed -= c.consumed
+		c.rule.WastedTime += c.time
+		c.rule.Wasted += c.consumed
+		c.sample.consumed -= int64(c.consumed)
+		c.sample.wasted += int64(c.consumed)
+	}
+
+	p.calls = p.calls[:i]
+}
+
+func (p *Profiler) recursive(n string) bool {
+	for _, f := range p.stack {
+		if f.name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Rules returns profiles of named parsers sorted by time in descending order.
+func (p *Profiler) Rules() []RuleProfile {
+	rs := make([]RuleProfile, 0, len(p.rules))
+
+	for _, r := range p.rules {
+		rs = append(rs, *r)
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Time != rs[j].Time {
+			return rs[i].Time > rs[j].Time
+		}
+
+		return rs[i].Name < rs[j].Name
+	})
+
+	return rs
+}
+
+// WriteReport writes a text report of profiles sorted by time.
+func (p *Profiler) WriteReport(w io.Writer) error {
+	t := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(t, "name\tcalls\tsuccesses\tfailures\ttime\tconsumed\twasted time\twasted\t")
+
+	for _, r := range p.Rules() {
+		fmt.Fprintf(
+			t,
+			"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n",
+			r.Name,
+			r.Calls,
+			r.Successes,
+			r.Failures,
+			r.Time,
+			r.Consumed,
+			r.WastedTime,
+			r.Wasted,
+		)
+	}
+
+	return t.Flush()
+}
+
+// WritePprof writes profiles in the gzip-compressed protocol buffer format of
+// pprof. Stacks of samples are ones of named parsers and sample values are
+// calls, self time, consumed characters and wasted characters.
+func (p *Profiler) WritePprof(w io.Writer) error {
+	e := newPprofEncoder()
+	b := &protoBuffer{}
+
+	for _, t := range [][2]string{
+		{"calls", "count"},
+		{"time", "nanoseconds"},
+		{"consumed", "characters"},
+		{"wasted", "characters"},
+	} {
+		b.message(1, e.valueType(t[0], t[1]))
+	}
+
+	ks := make([]string, 0, len(p.samples))
+
+	for k := range p.samples {
+		ks = append(ks, k)
+	}
+
+	sort.Strings(ks)
+
+	for _, k := range ks {
+		x := p.samples[k]
+		ls := make([]uint64, 0, len(x.stack))
+
+		for _, n := range x.stack {
+			ls = append(ls, e.location(n))
+		}
+
+		s := &protoBuffer{}
+		s.packed(1, ls)
+		s.packed(2, []uint64{uint64(x.calls), uint64(x.time), uint64(x.consumed), uint64(x.wasted)})
+		b.message(2, s)
+	}
+
+	for i := range e.functions {
+		l := &protoBuffer{}
+		l.uint64(1, uint64(i+1))
+		f := &protoBuffer{}
+		f.uint64(1, uint64(i+1))
+		l.message(4, f)
+		b.message(4, l)
+	}
+
+	for i, n := range e.functions {
+		f := &protoBuffer{}
+		f.uint64(1, uint64(i+1))
+		f.uint64(2, e.string(n))
+		f.uint64(3, e.string(n))
+		b.message(5, f)
+	}
+
+	b.message(11, e.valueType("calls", "count"))
+	b.uint64(14, e.string("time"))
+
+	for _, s := range e.strings {
+		b.bytes(6, []byte(s))
+	}
+
+	z := gzip.NewWriter(w)
+
+	if _, err := z.Write(b.Bytes()); err != nil {
+		return err
+	}
+
+	return z.Close()
+}
+
+type pprofEncoder struct {
+	strings     []string
+	stringIDs   map[string]uint64
+	functions   []string
+	functionIDs map[string]uint64
+}
+
+func newPprofEncoder() *pprofEncoder {
+	return &pprofEncoder{[]string{""}, map[string]uint64{"": 0}, nil, map[string]uint64{}}
+}
+
+func (e *pprofEncoder) string(s string) uint64 {
+	if i, ok := e.stringIDs[s]; ok {
+		return i
+	}
+
+	e.strings = append(e.strings, s)
+	e.stringIDs[s] = uint64(len(e.strings) - 1)
+
+	return e.stringIDs[s]
+}
+
+// Locations and functions share IDs as each function has only one location.
+func (e *pprofEncoder) location(n string) uint64 {
+	if i, ok := e.functionIDs[n]; ok {
+		return i
+	}
+
+	e.functions = append(e.functions, n)
+	e.functionIDs[n] = uint64(len(e.functions))
+
+	return e.functionIDs[n]
+}
+
+func (e *pprofEncoder) valueType(t, u string) *protoBuffer {
+	b := &protoBuffer{}
+	b.uint64(1, e.string(t))
+	b.uint64(2, e.string(u))
+	return b
+}
+
+type protoBuffer struct {
+	bytes.Buffer
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for ; x >= 0x80; x >>= 7 {
+		b.WriteByte(byte(x) | 0x80)
+	}
+
+	b.WriteByte(byte(x))
+}
+
+func (b *protoBuffer) uint64(f int, x uint64) {
+	b.varint(uint64(f) << 3)
+	b.varint(x)
+}
+
+func (b *protoBuffer) bytes(f int, bs []byte) {
+	b.varint(uint64(f)<<3 | 2)
+	b.varint(uint64(len(bs)))
+	b.Write(bs)
+}
+
+func (b *protoBuffer) packed(f int, xs []uint64) {
+	c := &protoBuffer{}
+
+	for _, x := range xs {
+		c.varint(x)
+	}
+
+	b.bytes(f, c.Bytes())
+}
+
+func (b *protoBuffer) message(f int, c *protoBuffer) {
+	b.bytes(f, c.Bytes())
+}
@@ -0,0 +1,279 @@
+package parcom_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProfiledState(str string) (*parcom.State, parcom.Parser, *parcom.Profiler) {
+	s := parcom.NewState(str)
+	p := parcom.NewProfiler()
+	s.SetProfiler(p)
+
+	var expr parcom.Parser
+	expr = s.Named("expr", s.Or(
+		s.Named("list", s.Wrap(s.Char('('), s.Many(s.Lazy(func() parcom.Parser { return expr })), s.Char(')'))),
+		s.Named("atom", s.Str("ab")),
+	))
+
+	return s, s.Exhaust(expr), p
+}
+
+func TestProfiler(t *testing.T) {
+	_, p, f := newProfiledState("(ab(ab)a")
+	_, err := p()
+
+	assert.Error(t, err)
+
+	rs := map[string]parcom.RuleProfile{}
+
+	for _, r := range f.Rules() {
+		rs[r.Name] = r
+	}
+
+	assert.Equal(t, 3, len(rs))
+	assert.Equal(t, 6, rs["expr"].Calls)
+	assert.Equal(t, 3, rs["expr"].Successes)
+	assert.Equal(t, 3, rs["expr"].Failures)
+	assert.Equal(t, 2, rs["atom"].Successes)
+	assert.Equal(t, 0, rs["atom"].Consumed)
+	assert.Equal(t, 3, rs["atom"].Failures)
+	assert.Equal(t, 5, rs["atom"].Wasted)
+	assert.Equal(t, 1, rs["list"].Successes)
+	assert.Equal(t, 0, rs["list"].Consumed)
+	assert.Equal(t, 5, rs["list"].Failures)
+	assert.Equal(t, 11, rs["list"].Wasted)
+}
+
+func TestProfilerWithDiscardedSuccesses(t *testing.T) {
+	for _, c := range []struct {
+		parser              func(*parcom.State) parcom.Parser
+		successes, consumed int
+	}{
+		{
+			func(s *parcom.State) parcom.Parser {
+				return s.Or(s.And(s.Named("a", s.Str("a")), s.Str("c")), s.Str("ab"))
+			},
+			1,
+			0,
+		},
+		{
+			func(s *parcom.State) parcom.Parser {
+				return s.Many(s.And(s.Named("a", s.Str("a")), s.Str("b")))
+			},
+			2,
+			1,
+		},
+	} {
+		s := parcom.NewState("aba")
+		p := parcom.NewProfiler()
+		s.SetProfiler(p)
+		_, err := c.parser(s)()
+
+		assert.Nil(t, err)
+
+		r := p.Rules()[0]
+
+		assert.Equal(t, c.successes, r.Successes)
+		assert.Equal(t, 0, r.Failures)
+		assert.Equal(t, c.consumed, r.Consumed)
+		assert.Equal(t, 1, r.Wasted)
+		assert.True(t, r.WastedTime > 0)
+	}
+}
+
+func TestProfilerRules(t *testing.T) {
+	_, p, f := newProfiledState("((ab)(ab))")
+	_, err := p()
+
+	assert.Nil(t, err)
+
+	rs := f.Rules()
+
+	for i := 1; i < len(rs); i++ {
+		assert.True(t, rs[i-1].Time >= rs[i].Time)
+	}
+}
+
+func TestProfilerWriteReport(t *testing.T) {
+	_, p, f := newProfiledState("(ab)")
+	_, err := p()
+
+	assert.Nil(t, err)
+
+	b := &bytes.Buffer{}
+	assert.Nil(t, f.WriteReport(b))
+
+	ls := strings.Split(strings.TrimSpace(b.String()), "\n")
+
+	assert.Equal(t, 4, len(ls))
+	assert.Equal(t, []string{"name", "calls", "successes", "failures"}, strings.Fields(ls[0])[:4])
+}
+
+func TestProfilerWritePprof(t *testing.T) {
+	_, p, f := newProfiledState("(ab)")
+	_, err := p()
+
+	assert.Nil(t, err)
+
+	b := &bytes.Buffer{}
+	assert.Nil(t, f.WritePprof(b))
+
+	r, err := gzip.NewReader(b)
+	assert.Nil(t, err)
+
+	bs, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+
+	ts, ss := decodePprof(t, bs)
+
+	assert.Equal(t, []string{"calls/count", "time/nanoseconds", "consumed/characters", "wasted/characters"}, ts)
+	assert.Equal(
+		t,
+		map[string][]uint64{
+			"expr":                {1, 4, 0},
+			"list;expr":           {1, 4, 0},
+			"expr;list;expr":      {2, 2, 0},
+			"list;expr;list;expr": {2, 0, 0},
+			"atom;expr;list;expr": {2, 2, 0},
+		},
+		ss,
+	)
+}
+
+// decodePprof decodes sample types and samples of calls, consumed characters
+// and wasted characters indexed by stacks from a pprof profile.
+func decodePprof(t *testing.T, bs []byte) ([]string, map[string][]uint64) {
+	strs, ts, fs, ls := []string{}, [][]uint64{}, map[uint64]uint64{}, map[uint64]uint64{}
+	ss := [][2][]uint64{}
+
+	for _, x := range decodeProto(t, bs) {
+		switch x.field {
+		case 1:
+			ts = append(ts, decodeProtoValues(t, x.bytes, 1, 2))
+		case 2:
+			ys := decodeProto(t, x.bytes)
+			assert.Equal(t, 2, len(ys))
+			ss = append(ss, [2][]uint64{decodePacked(t, ys[0].bytes), decodePacked(t, ys[1].bytes)})
+		case 4:
+			ys := decodeProto(t, x.bytes)
+			assert.Equal(t, 2, len(ys))
+			ls[ys[0].value] = decodeProtoValues(t, ys[1].bytes, 1)[0]
+		case 5:
+			ys := decodeProtoValues(t, x.bytes, 1, 2)
+			fs[ys[0]] = ys[1]
+		case 6:
+			strs = append(strs, string(x.bytes))
+		}
+	}
+
+	tss := []string{}
+
+	for _, t := range ts {
+		tss = append(tss, strs[t[0]]+"/"+strs[t[1]])
+	}
+
+	m := map[string][]uint64{}
+
+	for _, s := range ss {
+		ns := []string{}
+
+		for _, l := range s[0] {
+			ns = append(ns, strs[fs[ls[l]]])
+		}
+
+		m[strings.Join(ns, ";")] = []uint64{s[1][0], s[1][2], s[1][3]}
+	}
+
+	return tss, m
+}
+
+type protoField struct {
+	field int
+	value uint64
+	bytes []byte
+}
+
+func decodeProto(t *testing.T, bs []byte) []protoField {
+	xs := []protoField{}
+
+	for len(bs) > 0 {
+		k, n := decodeVarint(bs)
+		assert.True(t, n > 0)
+		bs = bs[n:]
+		x := protoField{field: int(k >> 3)}
+
+		switch k & 7 {
+		case 0:
+			x.value, n = decodeVarint(bs)
+			assert.True(t, n > 0)
+			bs = bs[n:]
+		case 2:
+			l, n := decodeVarint(bs)
+			assert.True(t, n > 0)
+			x.bytes, bs = bs[n:n+int(l)], bs[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %v", k&7)
+		}
+
+		xs = append(xs, x)
+	}
+
+	return xs
+}
+
+func decodeProtoValues(t *testing.T, bs []byte, fs ...int) []uint64 {
+	xs := make([]uint64, len(fs))
+
+	for _, x := range decodeProto(t, bs) {
+		for i, f := range fs {
+			if x.field == f {
+				xs[i] = x.value
+			}
+		}
+	}
+
+	return xs
+}
+
+func decodeVarint(bs []byte) (uint64, int) {
+	x := uint64(0)
+
+	for i, b := range bs {
+		x |= uint64(b&0x7f) << (7 * uint(i))
+
+		if b < 0x80 {
+			return x, i + 1
+		}
+	}
+
+	return 0, 0
+}
+
+func decodePacked(t *testing.T, bs []byte) []uint64 {
+	xs := []uint64{}
+
+	for len(bs) > 0 {
+		x, n := decodeVarint(bs)
+		assert.True(t, n > 0)
+		xs, bs = append(xs, x), bs[n:]
+	}
+
+	return xs
+}
+
+func TestProfilerWithoutNamedParsers(t *testing.T) {
+	s := parcom.NewState("foo")
+	p := parcom.NewProfiler()
+	s.SetProfiler(p)
+	_, err := s.Str("foo")()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(p.Rules()))
+}
@@ -0,0 +1,115 @@
+package parcom
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Regexp creates a parser which parses a string matched with a given regular
+// expression at a current position. It uses leftmost-first matching even if
+// the regular expression is in leftmost-longest mode.
+func (s *State) Regexp(re *regexp.Regexp) Parser {
+	return s.regexp(s.RegexpSubmatch(re))
+}
+
+// RegexpLongest is the same as Regexp but it uses leftmost-longest matching.
+func (s *State) RegexpLongest(re *regexp.Regexp) Parser {
+	return s.regexp(s.RegexpSubmatchLongest(re))
+}
+
+func (s *State) regexp(p Parser) Parser {
+	return func() (interface{}, error) {
+		ss, err := p()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return ss.([]string)[0], nil
+	}
+}
+
+// RegexpSubmatch is the same as Regexp but it returns a matched string and
+// submatches in []string. Unmatched submatches are empty strings.
+//
+// A previous character is visible to assertions, such as \b, and so ^ and \A
+// match only at the beginning of a source.
+func (s *State) RegexpSubmatch(re *regexp.Regexp) Parser {
+	return s.regexpSubmatch(re, false)
+}
+
+// RegexpSubmatchLongest is the same as RegexpSubmatch but it uses
+// leftmost-longest matching.
+func (s *State) RegexpSubmatchLongest(re *regexp.Regexp) Parser {
+	return s.regexpSubmatch(re, true)
+}
+
+func (s *State) regexpSubmatch(re *regexp.Regexp, longest bool) Parser {
+	m := fmt.Sprintf("expected pattern %q", re.String())
+	first := regexp.MustCompile(`^(?:` + re.String() + `)`)
+	rest := regexp.MustCompile(`^(?s:.)(?:` + re.String() + `)`)
+
+	if longest {
+		first.Longest()
+		rest.Longest()
+	}
+
+	return func() (interface{}, error) {
+		is := []int(nil)
+
+		if s.sourceIndex == 0 {
+			is = first.FindReaderSubmatchIndex(&runeReader{s.source, 0})
+		} else if is = rest.FindReaderSubmatchIndex(&runeReader{s.source, s.sourceIndex - 1}); is != nil {
+			n := utf8.RuneLen(s.source[s.sourceIndex-1])
+			is[0] = n
+
+			for i, j := range is {
+				if j >= 0 {
+					is[i] = j - n
+				}
+			}
+		}
+
+		if is == nil {
+			return nil, NewError(m, s)
+		}
+
+		rs := make([]rune, 0, is[1])
+
+		for n := 0; n < is[1]; n += utf8.RuneLen(rs[len(rs)-1]) {
+			rs = append(rs, s.currentRune())
+			s.readRune()
+		}
+
+		str := string(rs)
+		ss := make([]string, 0, len(is)/2)
+
+		for i := 0; i < len(is); i += 2 {
+			if is[i] < 0 {
+				ss = append(ss, "")
+			} else {
+				ss = append(ss, str[is[i]:is[i+1]])
+			}
+		}
+
+		return ss, nil
+	}
+}
+
+type runeReader struct {
+	runes []rune
+	index int
+}
+
+func (r *runeReader) ReadRune() (rune, int, error) {
+	if r.index >= len(r.runes) {
+		return 0, 0, io.EOF
+	}
+
+	c := r.runes[r.index]
+	r.index++
+
+	return c, utf8.RuneLen(c), nil
+}
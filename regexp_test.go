@@ -0,0 +1,145 @@
+package parcom_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexp(t *testing.T) {
+	s := parcom.NewState("3.14e+10 foo")
+	x, err := s.Regexp(regexp.MustCompile(`[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?`))()
+
+	assert.Equal(t, "3.14e+10", x)
+	assert.Nil(t, err)
+	assert.Equal(t, 9, s.Column())
+}
+
+func TestRegexpWithMultiByteCharacters(t *testing.T) {
+	s := parcom.NewState("あいう!")
+	x, err := s.Regexp(regexp.MustCompile(`\p{Hiragana}+`))()
+
+	assert.Equal(t, "あいう", x)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, s.Column())
+}
+
+func TestRegexpWithNewLines(t *testing.T) {
+	s := parcom.NewState("a\nb\nc")
+	x, err := s.Regexp(regexp.MustCompile(`(?s)a.b.`))()
+
+	assert.Equal(t, "a\nb\n", x)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.Line())
+	assert.Equal(t, 1, s.Column())
+}
+
+func TestRegexpMatchesOnlyAtCurrentPosition(t *testing.T) {
+	s := parcom.NewState("foo123")
+	_, err := s.Regexp(regexp.MustCompile(`[0-9]+`))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, err.(parcom.Error).Column())
+}
+
+func TestRegexpAfterOtherParsers(t *testing.T) {
+	s := parcom.NewState("foo123")
+	x, err := s.Exhaust(s.Prefix(s.Str("foo"), s.Regexp(regexp.MustCompile(`[0-9]+`))))()
+
+	assert.Equal(t, "123", x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpSubmatch(t *testing.T) {
+	s := parcom.NewState("key=value")
+	x, err := s.Exhaust(s.RegexpSubmatch(regexp.MustCompile(`(\w+)=(\w+)(;)?`)))()
+
+	assert.Equal(t, []string{"key=value", "key", "value", ""}, x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpSubmatchAfterOtherParsers(t *testing.T) {
+	s := parcom.NewState("x=key:value")
+	x, err := s.Exhaust(s.Prefix(s.Str("x="), s.RegexpSubmatch(regexp.MustCompile(`(\w+):(\w+)?`))))()
+
+	assert.Equal(t, []string{"key:value", "key", "value"}, x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpLongest(t *testing.T) {
+	s := parcom.NewState("ab")
+	x, err := s.RegexpLongest(regexp.MustCompile(`a|ab`))()
+
+	assert.Equal(t, "ab", x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpWithLeftmostFirstMatching(t *testing.T) {
+	s := parcom.NewState("ab")
+	x, err := s.Regexp(regexp.MustCompilePOSIX(`a|ab`))()
+
+	assert.Equal(t, "a", x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpSubmatchLongest(t *testing.T) {
+	s := parcom.NewState("ab")
+	x, err := s.RegexpSubmatchLongest(regexp.MustCompile(`(a|ab)`))()
+
+	assert.Equal(t, []string{"ab", "ab"}, x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpWithWordBoundary(t *testing.T) {
+	for _, c := range []struct {
+		prefix  string
+		success bool
+	}{
+		{"", true},
+		{" ", true},
+		{"a", false},
+		{"あ", true},
+	} {
+		s := parcom.NewState(c.prefix + "b")
+		_, err := s.Prefix(s.Str(c.prefix), s.Regexp(regexp.MustCompile(`\bb`)))()
+
+		assert.Equal(t, c.success, err == nil)
+	}
+}
+
+func TestRegexpWithNonWordBoundary(t *testing.T) {
+	s := parcom.NewState("ab")
+	x, err := s.Prefix(s.Str("a"), s.Regexp(regexp.MustCompile(`\Bb`)))()
+
+	assert.Equal(t, "b", x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpWithBeginningOfSource(t *testing.T) {
+	s := parcom.NewState("aa")
+	p := s.Regexp(regexp.MustCompile(`^a`))
+	_, err := p()
+
+	assert.Nil(t, err)
+
+	_, err = p()
+
+	assert.Error(t, err)
+}
+
+func TestRegexpWithBeginningOfLine(t *testing.T) {
+	s := parcom.NewState("a\nb")
+	x, err := s.Prefix(s.Str("a\n"), s.Regexp(regexp.MustCompile(`(?m)^b`)))()
+
+	assert.Equal(t, "b", x)
+	assert.Nil(t, err)
+}
+
+func TestRegexpError(t *testing.T) {
+	s := parcom.NewState("foo")
+	_, err := s.Regexp(regexp.MustCompile(`[0-9]+`))()
+
+	assert.Equal(t, `expected pattern "[0-9]+"`, err.Error())
+}
@@ -0,0 +1,53 @@
+package parcom
+
+// Position is a position in a source.
+type Position struct {
+	// Offset is a byte offset in a source.
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Span is a range in a source.
+type Span struct {
+	Start, End Position
+}
+
+// Spanned is a parsing result with its span.
+type Spanned struct {
+	Value interface{}
+	Span
+}
+
+// Consumed creates a parser which returns a part of a source parsed by a given
+// parser.
+func (s *State) Consumed(p Parser) Parser {
+	return func() (interface{}, error) {
+		i := s.sourceIndex
+
+		if _, err := p(); err != nil {
+			return nil, err
+		}
+
+		return string(s.source[i:s.sourceIndex]), nil
+	}
+}
+
+// Spanned creates a parser which returns a result of a given parser with its
+// span in Spanned.
+func (s *State) Spanned(p Parser) Parser {
+	return func() (interface{}, error) {
+		b := s.currentPosition()
+		x, err := p()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Spanned{x, Span{b, s.currentPosition()}}, nil
+	}
+}
+
+func (s State) currentPosition() Position {
+	return Position{s.byteIndex, s.Line(), s.Column()}
+}
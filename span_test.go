@@ -0,0 +1,56 @@
+package parcom_test
+
+import (
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumed(t *testing.T) {
+	s := parcom.NewState("foo  bar;")
+	x, err := s.Consumed(s.And(s.Str("foo"), s.Many(s.Char(' ')), s.Void(s.Str("bar"))))()
+
+	assert.Equal(t, "foo  bar", x)
+	assert.Nil(t, err)
+}
+
+func TestConsumedWithArbitraryResult(t *testing.T) {
+	s := parcom.NewState("42")
+	x, err := s.Consumed(s.Int(parcom.GoNumberOptions))()
+
+	assert.Equal(t, "42", x)
+	assert.Nil(t, err)
+}
+
+func TestConsumedError(t *testing.T) {
+	s := parcom.NewState("foo")
+	_, err := s.Consumed(s.Str("bar"))()
+
+	assert.Error(t, err)
+}
+
+func TestSpanned(t *testing.T) {
+	s := parcom.NewState("é\nfoo\nbar")
+	x, err := s.Prefix(s.Str("é\n"), s.Spanned(s.Str("foo\nba")))()
+
+	assert.Equal(
+		t,
+		parcom.Spanned{
+			Value: "foo\nba",
+			Span: parcom.Span{
+				Start: parcom.Position{Offset: 3, Line: 2, Column: 1},
+				End:   parcom.Position{Offset: 9, Line: 3, Column: 3},
+			},
+		},
+		x,
+	)
+	assert.Nil(t, err)
+}
+
+func TestSpannedError(t *testing.T) {
+	s := parcom.NewState("foo")
+	_, err := s.Spanned(s.Str("bar"))()
+
+	assert.Error(t, err)
+}
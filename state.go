@@ -1,14 +1,55 @@
 package parcom
 
+import "unicode/utf8"
+
 // State is a parser state.
 type State struct {
+	text                                string
 	source                              []rune
 	sourceIndex, lineIndex, columnIndex int
+	byteIndex                           int
+	position                            position
+	userState                           interface{}
+	scope                               *scope
+	limits                              *limitState
+	tracer                              *tracer
+	profiler                            *Profiler
+	profiled                            int
+	trivia                              Parser
+	stringifiers                        []func(interface{}) (string, bool)
 }
 
 // NewState creates a parser state.
 func NewState(s string) *State {
-	return &State{[]rune(s), 0, 0, 0}
+	return &State{s, []rune(s), 0, 0, 0, 0, position{-1, -1}, nil, nil, nil, nil, nil, 0, nil, nil}
+}
+
+// Checkpoint is a snapshot of a parser state.
+type Checkpoint struct {
+	state State
+}
+
+// Checkpoint saves a current parser state including a position saved by
+// PositionalState.
+func (s *State) Checkpoint() Checkpoint {
+	return Checkpoint{*s}
+}
+
+// Rewind restores a parser state saved by Checkpoint.
+func (s *State) Rewind(c Checkpoint) {
+	*s = c.state
+}
+
+// UserState returns a user-defined state.
+func (s State) UserState() interface{} {
+	return s.userState
+}
+
+// SetUserState sets a user-defined state. It is restored on backtracking
+// together with the other parser state. Therefore, it should be an immutable
+// value and be replaced instead of being modified in place.
+func (s *State) SetUserState(x interface{}) {
+	s.userState = x
 }
 
 func (s State) exhausted() bool {
@@ -31,6 +72,8 @@ func (s *State) readRune() {
 		s.columnIndex++
 	}
 
+	_, n := utf8.DecodeRuneInString(s.text[s.byteIndex:])
+	s.byteIndex += n
 	s.sourceIndex++
 }
 
@@ -43,3 +86,60 @@ func (s State) Line() int {
 func (s State) Column() int {
 	return s.columnIndex + 1
 }
+
+// Offset returns a current byte offset in a source. Invalid bytes in UTF-8
+// are counted as they are in the source.
+func (s State) Offset() int {
+	return s.byteIndex
+}
+
+// Peek returns a current character. It returns false if a source is
+// exhausted.
+func (s State) Peek() (rune, bool) {
+	return s.currentRune(), !s.exhausted()
+}
+
+// PeekN returns at most n characters from a current position. It returns an
+// empty string if n is negative.
+func (s State) PeekN(n int) string {
+	if m := len(s.source) - s.sourceIndex; n > m {
+		n = m
+	} else if n < 0 {
+		n = 0
+	}
+
+	return string(s.source[s.sourceIndex : s.sourceIndex+n])
+}
+
+// Remaining returns the number of characters left in a source.
+func (s State) Remaining() int {
+	return len(s.source) - s.sourceIndex
+}
+
+// AtEOF returns true if a source is exhausted.
+func (s State) AtEOF() bool {
+	return s.exhausted()
+}
+
+// LineColumn returns line and column numbers at a given byte offset in a
+// source. It returns false if the offset is out of the source or not at a
+// character boundary.
+func (s State) LineColumn(o int) (int, int, bool) {
+	l, c := 1, 1
+
+	for b, r := range s.text {
+		if b == o {
+			return l, c, true
+		} else if b > o {
+			return 0, 0, false
+		}
+
+		if r == '\n' {
+			l, c = l+1, 1
+		} else {
+			c++
+		}
+	}
+
+	return l, c, len(s.text) == o
+}
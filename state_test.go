@@ -35,3 +35,212 @@ func TestStateColumnIncrements(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 3, s.Column())
 }
+
+func TestStateCheckpoint(t *testing.T) {
+	s := parcom.NewState("foo\nbar")
+	c := s.Checkpoint()
+	_, err := s.Str("foo\nb")()
+
+	assert.Nil(t, err)
+
+	s.Rewind(c)
+
+	assert.Equal(t, 1, s.Line())
+	assert.Equal(t, 1, s.Column())
+
+	x, err := s.Str("foo")()
+
+	assert.Equal(t, "foo", x)
+	assert.Nil(t, err)
+}
+
+func TestStateCheckpointWithCustomCombinator(t *testing.T) {
+	s := parcom.NewState("foo")
+	try := func(p parcom.Parser) parcom.Parser {
+		return func() (interface{}, error) {
+			c := s.Checkpoint()
+
+			if _, err := p(); err != nil {
+				s.Rewind(c)
+			}
+
+			return nil, nil
+		}
+	}
+
+	_, err := s.Exhaust(s.And(try(s.Str("fob")), s.Str("foo")))()
+
+	assert.Nil(t, err)
+}
+
+func TestStateOffset(t *testing.T) {
+	str := "aé\nb"
+	s := parcom.NewState(str)
+	_, err := s.Str("aé")()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.Offset())
+	assert.Equal(t, "\nb", str[s.Offset():])
+}
+
+func TestStateOffsetWithInvalidUTF8(t *testing.T) {
+	str := "\xffa\xe3\x81b"
+	s := parcom.NewState(str)
+
+	for _, o := range []int{1, 2, 3, 4, 5} {
+		_, err := s.AnyChar()()
+
+		assert.Nil(t, err)
+		assert.Equal(t, o, s.Offset())
+	}
+
+	assert.Equal(t, "", str[s.Offset():])
+}
+
+func TestStatePeek(t *testing.T) {
+	s := parcom.NewState("é")
+	r, ok := s.Peek()
+
+	assert.Equal(t, 'é', r)
+	assert.True(t, ok)
+
+	_, err := s.AnyChar()()
+	assert.Nil(t, err)
+
+	_, ok = s.Peek()
+
+	assert.False(t, ok)
+}
+
+func TestStatePeekN(t *testing.T) {
+	s := parcom.NewState("fooé")
+
+	assert.Equal(t, "", s.PeekN(0))
+	assert.Equal(t, "", s.PeekN(-1))
+	assert.Equal(t, "fo", s.PeekN(2))
+	assert.Equal(t, "fooé", s.PeekN(42))
+	assert.Equal(t, 1, s.Column())
+}
+
+func TestStateRemaining(t *testing.T) {
+	s := parcom.NewState("fooé")
+	_, err := s.Str("f")()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.Remaining())
+}
+
+func TestStateAtEOF(t *testing.T) {
+	s := parcom.NewState("f")
+
+	assert.False(t, s.AtEOF())
+
+	_, err := s.Str("f")()
+
+	assert.Nil(t, err)
+	assert.True(t, s.AtEOF())
+}
+
+func TestStateLineColumn(t *testing.T) {
+	s := parcom.NewState("aé\nb")
+
+	for o, lc := range map[int][2]int{0: {1, 1}, 1: {1, 2}, 3: {1, 3}, 4: {2, 1}, 5: {2, 2}} {
+		l, c, ok := s.LineColumn(o)
+
+		assert.True(t, ok)
+		assert.Equal(t, lc, [2]int{l, c})
+	}
+
+	for _, o := range []int{-1, 2, 6} {
+		_, _, ok := s.LineColumn(o)
+
+		assert.False(t, ok)
+	}
+}
+
+func TestStateLineColumnWithInvalidUTF8(t *testing.T) {
+	s := parcom.NewState("\xffa\n\xe3\x81b")
+
+	for o, lc := range map[int][2]int{1: {1, 2}, 2: {1, 3}, 3: {2, 1}, 4: {2, 2}, 5: {2, 3}, 6: {2, 4}} {
+		l, c, ok := s.LineColumn(o)
+
+		assert.True(t, ok)
+		assert.Equal(t, lc, [2]int{l, c})
+	}
+}
+
+func TestStateUserState(t *testing.T) {
+	s := parcom.NewState("")
+
+	assert.Nil(t, s.UserState())
+
+	s.SetUserState(42)
+
+	assert.Equal(t, 42, s.UserState())
+}
+
+func TestStateUserStateWithBacktracking(t *testing.T) {
+	s := parcom.NewState("typedef foo; foo * bar;")
+	s.SetTrivia(s.Space())
+
+	type names map[string]bool
+
+	s.SetUserState(names{})
+	declare := func(p parcom.Parser) parcom.Parser {
+		return s.App(func(x interface{}) (interface{}, error) {
+			ns := names{x.(string): true}
+
+			for n := range s.UserState().(names) {
+				ns[n] = true
+			}
+
+			s.SetUserState(ns)
+
+			return x, nil
+		}, p)
+	}
+	name := s.Token(s.Stringify(s.Many1(s.Letter())))
+	typeName := s.App(func(x interface{}) (interface{}, error) {
+		if !s.UserState().(names)[x.(string)] {
+			return nil, parcom.NewError("undeclared type", s)
+		}
+
+		return x, nil
+	}, name)
+
+	x, err := s.Exhaust(s.Many(s.Or(
+		s.And(s.Symbol("typedef"), declare(name), s.Symbol(";")),
+		s.And(typeName, s.Symbol("*"), declare(name), s.Symbol(";")),
+		s.And(name, s.Symbol("*"), name, s.Symbol(";")),
+	)))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "*", x.([]interface{})[1].([]interface{})[1])
+	assert.Equal(t, names{"foo": true, "bar": true}, s.UserState())
+}
+
+func TestStateUserStateRestoredOnBacktracking(t *testing.T) {
+	s := parcom.NewState("b")
+	s.SetUserState(0)
+	set := func(x int, p parcom.Parser) parcom.Parser {
+		return func() (interface{}, error) {
+			s.SetUserState(x)
+			return p()
+		}
+	}
+
+	_, err := s.Or(set(1, s.Char('a')), s.Char('b'))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, s.UserState())
+
+	_, err = s.Many(set(2, s.Char('c')))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, s.UserState())
+
+	_, err = s.Maybe(set(3, s.Char('c')))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, s.UserState())
+}
@@ -0,0 +1,221 @@
+package parcom
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// StringOptions is a syntax of quoted string literals.
+type StringOptions struct {
+	// Quotes are characters allowed as quotes. Strings are closed by the same
+	// characters as opening ones.
+	Quotes string
+	// Escapes maps characters after backslashes to ones they represent.
+	Escapes map[rune]rune
+	// Hex enables \xhh escapes.
+	Hex bool
+	// Unicode enables \uhhhh escapes.
+	Unicode bool
+	// LongUnicode enables \Uhhhhhhhh escapes.
+	LongUnicode bool
+	// Octal enables escapes of up to 3 octal digits.
+	Octal bool
+	// ExactOctal requires exactly 3 digits in octal escapes.
+	ExactOctal bool
+	// Surrogates combines UTF-16 surrogate pairs in consecutive \uhhhh
+	// escapes.
+	Surrogates bool
+	// ByteEscapes makes hex and octal escapes represent bytes rather than
+	// Unicode code points.
+	ByteEscapes bool
+	// Raw disables escapes.
+	Raw bool
+	// MultiLine allows new lines in strings.
+	MultiLine bool
+	// NoControlCharacters rejects unescaped control characters from U+0000 to
+	// U+001F in strings.
+	NoControlCharacters bool
+}
+
+var (
+	// JSONStringOptions is a syntax of strings in JSON.
+	JSONStringOptions = StringOptions{
+		Quotes: `"`,
+		Escapes: map[rune]rune{
+			'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t',
+		},
+		Unicode:             true,
+		Surrogates:          true,
+		NoControlCharacters: true,
+	}
+	// GoStringOptions is a syntax of interpreted string literals in Go.
+	GoStringOptions = StringOptions{
+		Quotes: `"`,
+		Escapes: map[rune]rune{
+			'"': '"', '\\': '\\', 'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v',
+		},
+		Hex:         true,
+		Unicode:     true,
+		LongUnicode: true,
+		Octal:       true,
+		ExactOctal:  true,
+		ByteEscapes: true,
+	}
+	// GoRawStringOptions is a syntax of raw string literals in Go.
+	GoRawStringOptions = StringOptions{Quotes: "`", Raw: true, MultiLine: true}
+	// PythonStringOptions is a syntax of string literals in Python.
+	PythonStringOptions = StringOptions{
+		Quotes: `"'`,
+		Escapes: map[rune]rune{
+			'"': '"', '\'': '\'', '\\': '\\', 'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v',
+		},
+		Hex:         true,
+		Unicode:     true,
+		LongUnicode: true,
+		Octal:       true,
+	}
+)
+
+// QuotedString creates a parser of a quoted string literal. Its result is a
+// decoded string.
+func (s *State) QuotedString(o StringOptions) Parser {
+	return func() (interface{}, error) {
+		ss := *s
+		q := s.currentRune()
+
+		if s.exhausted() || !strings.ContainsRune(o.Quotes, q) {
+			return nil, newInvalidCharacterError(s)
+		}
+
+		s.readRune()
+		bs := []byte{}
+
+		for {
+			r := s.currentRune()
+
+			switch {
+			case s.exhausted() || r == '\n' && !o.MultiLine:
+				return nil, NewError("unterminated string", &ss)
+			case r < 0x20 && o.NoControlCharacters:
+				return nil, newRawError("control character in string", s)
+			case r == q:
+				s.readRune()
+				return string(bs), nil
+			case r == '\\' && !o.Raw:
+				var err error
+
+				if bs, err = s.escapeSequence(o, bs); err != nil {
+					return nil, err
+				}
+			default:
+				bs = appendRune(bs, r)
+				s.readRune()
+			}
+		}
+	}
+}
+
+func (s *State) escapeSequence(o StringOptions, bs []byte) ([]byte, error) {
+	ss := *s
+	s.readRune()
+	r := s.currentRune()
+
+	if e, ok := o.Escapes[r]; ok && !s.exhausted() {
+		s.readRune()
+		return appendRune(bs, e), nil
+	}
+
+	switch {
+	case r == 'x' && o.Hex:
+		s.readRune()
+
+		if c, ok := s.hexCode(2); ok {
+			return appendCode(bs, c, o.ByteEscapes), nil
+		}
+	case r == 'u' && o.Unicode:
+		s.readRune()
+
+		if c, ok := s.hexCode(4); ok {
+			return s.appendUnicode(o, bs, c, &ss)
+		}
+	case r == 'U' && o.LongUnicode:
+		s.readRune()
+
+		if c, ok := s.hexCode(8); ok {
+			return s.appendUnicode(o, bs, c, &ss)
+		}
+	case isDigit(r, 8) && o.Octal:
+		c, i := rune(0), 0
+
+		for ; i < 3 && isDigit(s.currentRune(), 8); i++ {
+			c = c*8 + s.currentRune() - '0'
+			s.readRune()
+		}
+
+		if c <= 0xff && (i == 3 || !o.ExactOctal) {
+			return appendCode(bs, c, o.ByteEscapes), nil
+		}
+	}
+
+	return nil, NewError("invalid escape sequence", &ss)
+}
+
+func (s *State) appendUnicode(o StringOptions, bs []byte, c rune, ss *State) ([]byte, error) {
+	if o.Surrogates && utf16.IsSurrogate(c) {
+		c = s.lowSurrogate(c)
+	}
+
+	if !utf8.ValidRune(c) {
+		return nil, NewError("invalid Unicode code point", ss)
+	}
+
+	return appendRune(bs, c), nil
+}
+
+func (s *State) lowSurrogate(c rune) rune {
+	ss := *s
+
+	if s.PeekN(2) == `\u` {
+		s.readRune()
+		s.readRune()
+
+		if d, ok := s.hexCode(4); ok && utf16.DecodeRune(c, d) != utf8.RuneError {
+			return utf16.DecodeRune(c, d)
+		}
+	}
+
+	*s = ss
+
+	return c
+}
+
+func (s *State) hexCode(n int) (rune, bool) {
+	c := rune(0)
+
+	for i := 0; i < n; i++ {
+		r := s.currentRune()
+
+		if !isDigit(r, 16) {
+			return 0, false
+		}
+
+		c = c*16 + rune(strings.IndexRune("0123456789abcdef", r|0x20))
+		s.readRune()
+	}
+
+	return c, true
+}
+
+func appendCode(bs []byte, c rune, b bool) []byte {
+	if b {
+		return append(bs, byte(c))
+	}
+
+	return appendRune(bs, c)
+}
+
+func appendRune(bs []byte, r rune) []byte {
+	b := make([]byte, utf8.UTFMax)
+	return append(bs, b[:utf8.EncodeRune(b, r)]...)
+}
@@ -0,0 +1,120 @@
+package parcom_test
+
+import (
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotedString(t *testing.T) {
+	for str, x := range map[string]string{
+		`""`:              "",
+		`"foo"`:           "foo",
+		`"a\"b"`:          `a"b`,
+		`"\n\t\\\/"`:      "\n\t\\/",
+		`"\u3042"`:        "あ",
+		`"\ud83d\ude00"`:  "😀",
+		`"日本語"`:           "日本語",
+		`"e\u0301 x"`:     "e\u0301 x",
+		`"\uD83D\uDE00A"`: "\U0001F600A",
+	} {
+		s := parcom.NewState(str)
+		y, err := s.Exhaust(s.QuotedString(parcom.JSONStringOptions))()
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, x, y, str)
+	}
+}
+
+func TestQuotedStringError(t *testing.T) {
+	for str, c := range map[string]int{
+		`"\x41"`:    2,
+		`"ab\q"`:    4,
+		`"\u12"`:    2,
+		`"\ud83d"`:  2,
+		`"\ud83dA"`: 2,
+		`"\ude00"`:  2,
+	} {
+		s := parcom.NewState(str)
+		_, err := s.QuotedString(parcom.JSONStringOptions)()
+
+		assert.Error(t, err, str)
+		assert.Equal(t, c, err.(parcom.Error).Column(), str)
+	}
+}
+
+func TestQuotedStringErrorWithControlCharacters(t *testing.T) {
+	for _, str := range []string{"\"a\tb\"", "\"a\x01b\"", "\"a\x00b\""} {
+		s := parcom.NewState(str)
+		_, err := s.QuotedString(parcom.JSONStringOptions)()
+
+		assert.Equal(t, "control character in string", err.Error(), str)
+		assert.Equal(t, 3, err.(parcom.Error).Column(), str)
+	}
+}
+
+func TestQuotedStringErrorWithUnterminatedString(t *testing.T) {
+	for _, str := range []string{`x = "foo`, "x = \"foo\nbar\"", `x = "foo\"`} {
+		s := parcom.NewState(str)
+		_, err := s.Prefix(s.Str("x = "), s.QuotedString(parcom.JSONStringOptions))()
+
+		assert.Equal(t, "unterminated string", err.Error(), str)
+		assert.Equal(t, 1, err.(parcom.Error).Line())
+		assert.Equal(t, 5, err.(parcom.Error).Column())
+	}
+}
+
+func TestQuotedStringErrorWithoutQuote(t *testing.T) {
+	s := parcom.NewState("foo")
+	_, err := s.QuotedString(parcom.JSONStringOptions)()
+
+	assert.Error(t, err)
+}
+
+func TestQuotedStringWithGoOptions(t *testing.T) {
+	for str, x := range map[string]string{
+		`"\a\v"`:       "\a\v",
+		`"\x41\xff"`:   "A\xff",
+		`"\101\377"`:   "A\xff",
+		`"\000"`:       "\x00",
+		"\"a\tb\"":     "a\tb",
+		`"\U0001F600"`: "😀",
+		"`a\\n\nb`":    "a\\n\nb",
+	} {
+		s := parcom.NewState(str)
+		y, err := s.Exhaust(
+			s.Or(s.QuotedString(parcom.GoStringOptions), s.QuotedString(parcom.GoRawStringOptions)),
+		)()
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, x, y, str)
+	}
+}
+
+func TestQuotedStringErrorWithGoOptions(t *testing.T) {
+	for _, str := range []string{`"\400"`, `"\0"`, `"\12"`, `"\12x"`, `"\U00110000"`, `"\ud800"`, `"\xg0"`} {
+		s := parcom.NewState(str)
+		_, err := s.QuotedString(parcom.GoStringOptions)()
+
+		assert.Error(t, err, str)
+		assert.Equal(t, 2, err.(parcom.Error).Column(), str)
+	}
+}
+
+func TestQuotedStringWithPythonOptions(t *testing.T) {
+	for str, x := range map[string]string{
+		`'a"b'`:    `a"b`,
+		`"a'b"`:    `a'b`,
+		`'\''`:     `'`,
+		`'\xff'`:   "\u00ff",
+		`'\0'`:     "\x00",
+		`'\u3042'`: "\u3042",
+	} {
+		s := parcom.NewState(str)
+		y, err := s.Exhaust(s.QuotedString(parcom.PythonStringOptions))()
+
+		assert.Nil(t, err, str)
+		assert.Equal(t, x, y, str)
+	}
+}
@@ -0,0 +1,92 @@
+package parcom
+
+import "fmt"
+
+// Symbols and scopes are persistent linked lists so that snapshots of parser
+// states share them.
+type symbol struct {
+	name string
+	kind interface{}
+	next *symbol
+}
+
+type scope struct {
+	symbols *symbol
+	parent  *scope
+}
+
+// Scope creates a parser which runs a given parser in a new lexical scope.
+// Symbols declared in the scope are not visible after it.
+func (s *State) Scope(p Parser) Parser {
+	return func() (interface{}, error) {
+		c := s.scope
+		s.scope = &scope{nil, c}
+		defer func() { s.scope = c }()
+
+		return p()
+	}
+}
+
+// Declare creates a parser which declares a name parsed by a given parser as a
+// symbol of a given kind in a current scope. Kinds must be comparable. Its
+// result is the name.
+func (s *State) Declare(p Parser, kind interface{}) Parser {
+	return func() (interface{}, error) {
+		n, err := s.symbolName(p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		c := s.scope
+
+		if c == nil {
+			c = &scope{}
+		}
+
+		s.scope = &scope{&symbol{n, kind, c.symbols}, c.parent}
+
+		return n, nil
+	}
+}
+
+// Lookup creates a parser which parses a name by a given parser and fails
+// unless it is declared as a symbol of a given kind in current scopes. Its
+// result is the name.
+func (s *State) Lookup(p Parser, kind interface{}) Parser {
+	return func() (interface{}, error) {
+		ss := *s
+		n, err := s.symbolName(p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for c := s.scope; c != nil; c = c.parent {
+			for y := c.symbols; y != nil; y = y.next {
+				if y.name == n && y.kind == kind {
+					return n, nil
+				}
+			}
+		}
+
+		return nil, newRawError(fmt.Sprintf("undeclared name '%v'", n), &ss)
+	}
+}
+
+func (s *State) symbolName(p Parser) (string, error) {
+	ss := *s
+	x, err := p()
+
+	if err != nil {
+		return "", err
+	}
+
+	n, ok := s.stringify(x)
+
+	if !ok {
+		return "", newRawError(fmt.Sprintf("cannot stringify result of type %T", x), &ss)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,108 @@
+package parcom_test
+
+import (
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+type symbolKind int
+
+const (
+	typeSymbol symbolKind = iota
+	variableSymbol
+)
+
+type symbolState struct {
+	*parcom.State
+}
+
+func newSymbolState(str string) *symbolState {
+	s := &symbolState{parcom.NewState(str)}
+	s.SetTrivia(s.Space())
+	return s
+}
+
+func (s *symbolState) name() parcom.Parser {
+	return s.Token(s.Stringify(s.Many1(s.Letter())))
+}
+
+func (s *symbolState) statements() parcom.Parser {
+	return s.Many(s.Or(
+		s.And(s.Symbol("type"), s.Declare(s.name(), typeSymbol), s.Symbol(";")),
+		s.And(s.Lookup(s.name(), typeSymbol), s.Symbol("*"), s.Declare(s.name(), variableSymbol), s.Symbol(";")),
+		s.And(s.Lookup(s.name(), variableSymbol), s.Symbol("*"), s.Lookup(s.name(), variableSymbol), s.Symbol(";")),
+		s.Wrap(s.Symbol("{"), s.Scope(s.Lazy(s.statements)), s.Symbol("}")),
+	))
+}
+
+func TestDeclareAndLookup(t *testing.T) {
+	for _, str := range []string{
+		"",
+		"type a;",
+		"type a; a * b;",
+		"type a; a * b; b * b;",
+		"type a; { a * b; b * b; }",
+		"type a; { type b; b * c; } a * b; b * b;",
+	} {
+		s := newSymbolState(str)
+		_, err := s.Exhaust(s.statements())()
+
+		assert.Nil(t, err, str)
+	}
+}
+
+func TestDeclareAndLookupWithAmbiguity(t *testing.T) {
+	s := newSymbolState("type a; a * b; b * b;")
+	x, err := s.Exhaust(s.statements())()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"a", "*", "b", ";"}, x.([]interface{})[1])
+	assert.Equal(t, []interface{}{"b", "*", "b", ";"}, x.([]interface{})[2])
+}
+
+func TestLookupError(t *testing.T) {
+	s := parcom.NewState("foo")
+	_, err := s.Lookup(s.Str("foo"), typeSymbol)()
+
+	assert.Error(t, err)
+	assert.Equal(t, "undeclared name 'foo'", err.Error())
+	assert.Equal(t, 1, err.(parcom.Error).Column())
+}
+
+func TestLookupErrorWithScope(t *testing.T) {
+	s := newSymbolState("{ type a; } a * b;")
+	_, err := s.Exhaust(s.statements())()
+
+	assert.Error(t, err)
+}
+
+func TestLookupErrorWithDifferentKind(t *testing.T) {
+	s := newSymbolState("type a; b * a;")
+	_, err := s.Exhaust(s.statements())()
+
+	assert.Error(t, err)
+}
+
+func TestDeclareWithBacktracking(t *testing.T) {
+	s := parcom.NewState("foo;")
+	_, err := s.Or(
+		s.And(s.Declare(s.Str("foo"), typeSymbol), s.Str(",")),
+		s.And(s.Str("foo"), s.Str(";")),
+	)()
+
+	assert.Nil(t, err)
+
+	s = parcom.NewState("foo;foo")
+	_, err = s.And(
+		s.Or(
+			s.And(s.Declare(s.Str("foo"), typeSymbol), s.Str(",")),
+			s.And(s.Str("foo"), s.Str(";")),
+		),
+		s.Lookup(s.Str("foo"), typeSymbol),
+	)()
+
+	assert.Equal(t, "undeclared name 'foo'", err.Error())
+	assert.Equal(t, 5, err.(parcom.Error).Column())
+}
@@ -0,0 +1,155 @@
+package parcom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TraceEventKind is a kind of trace events.
+type TraceEventKind string
+
+const (
+	// TraceEnter is a kind of events emitted when named parsers start.
+	TraceEnter TraceEventKind = "enter"
+	// TraceSuccess is a kind of events emitted when named parsers succeed.
+	TraceSuccess TraceEventKind = "success"
+	// TraceFailure is a kind of events emitted when named parsers fail.
+	TraceFailure TraceEventKind = "failure"
+	// TraceBacktrack is a kind of events emitted when combinators backtrack.
+	TraceBacktrack TraceEventKind = "backtrack"
+)
+
+// TraceEvent is an event of parsing.
+type TraceEvent struct {
+	Kind TraceEventKind `json:"kind"`
+	// Name is a name of a parser. It is empty for backtrack events.
+	Name  string `json:"name,omitempty"`
+	Depth int    `json:"depth"`
+	// Position is a start position for enter events, an end position for
+	// success events, a position where parsers stop for failure events and a
+	// restored position for backtrack events.
+	Position Position `json:"position"`
+	// Consumed is a part of a source parsed by a parser on success.
+	Consumed string `json:"consumed,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// SavedLine and SavedColumn are a position saved by PositionalState. They
+	// are 0 if no position is saved.
+	SavedLine   int `json:"savedLine,omitempty"`
+	SavedColumn int `json:"savedColumn,omitempty"`
+}
+
+func (e TraceEvent) String() string {
+	ss := []string{
+		strings.Repeat("  ", e.Depth) + string(e.Kind),
+	}
+
+	if e.Name != "" {
+		ss = append(ss, e.Name)
+	}
+
+	ss = append(ss, fmt.Sprintf("at %d:%d", e.Position.Line, e.Position.Column))
+
+	if e.SavedLine != 0 {
+		ss = append(ss, fmt.Sprintf("saved %d:%d", e.SavedLine, e.SavedColumn))
+	}
+
+	if e.Kind == TraceSuccess {
+		ss = append(ss, fmt.Sprintf("%q", e.Consumed))
+	} else if e.Kind == TraceFailure {
+		ss = append(ss, e.Error)
+	}
+
+	return strings.Join(ss, " ")
+}
+
+type tracer struct {
+	handler func(TraceEvent)
+	depth   int
+}
+
+// SetTraceFunc enables tracing of named parsers with a function which handles
+// trace events.
+func (s *State) SetTraceFunc(f func(TraceEvent)) {
+	s.tracer = &tracer{f, 0}
+}
+
+// SetTraceWriter enables tracing of named parsers in plain text written to a
+// given writer.
+func (s *State) SetTraceWriter(w io.Writer) {
+	s.SetTraceFunc(func(e TraceEvent) {
+		fmt.Fprintln(w, e)
+	})
+}
+
+// SetJSONTraceWriter enables tracing of named parsers in JSON lines written to
+// a given writer.
+func (s *State) SetJSONTraceWriter(w io.Writer) {
+	e := json.NewEncoder(w)
+
+	s.SetTraceFunc(func(x TraceEvent) {
+		// Trace events are always encodable.
+		_ = e.Encode(x)
+	})
+}
+
+// Named creates a parser with a name. Named parsers are units of tracing and
+// profiling.
+func (s *State) Named(n string, p Parser) Parser {
+	return func() (interface{}, error) {
+		t, f := s.tracer, s.profiler
+
+		if t == nil && f == nil {
+			return p()
+		}
+
+		i := s.sourceIndex
+		s.trace(TraceEnter, n, "", "")
+
+		if t != nil {
+			t.depth++
+		}
+
+		if f != nil {
+			f.enter(n)
+		}
+
+		x, err := p()
+
+		if f != nil {
+			f.leave(s.sourceIndex-i, err == nil)
+			s.profiled = len(f.calls)
+		}
+
+		if t != nil {
+			t.depth--
+		}
+
+		if err != nil {
+			s.trace(TraceFailure, n, "", err.Error())
+			return nil, err
+		}
+
+		s.trace(TraceSuccess, n, string(s.source[i:s.sourceIndex]), "")
+
+		return x, nil
+	}
+}
+
+func (s *State) trace(k TraceEventKind, n, c, e string) {
+	if s.tracer == nil {
+		return
+	}
+
+	s.tracer.handler(TraceEvent{
+		k,
+		n,
+		s.tracer.depth,
+		s.currentPosition(),
+		c,
+		e,
+		s.position.lineIndex + 1,
+		s.position.columnIndex + 1,
+	})
+}
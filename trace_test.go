@@ -0,0 +1,103 @@
+package parcom_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTraceWriter(t *testing.T) {
+	s := parcom.NewState("foo")
+	b := &bytes.Buffer{}
+	s.SetTraceWriter(b)
+	_, err := s.Named("expr", s.Or(s.Named("bar", s.Str("bar")), s.Named("foo", s.Str("foo"))))()
+
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		strings.Join([]string{
+			"enter expr at 1:1",
+			"  enter bar at 1:1",
+			"  failure bar at 1:1 invalid character 'f'",
+			"  backtrack at 1:1",
+			"  enter foo at 1:1",
+			`  success foo at 1:4 "foo"`,
+			`success expr at 1:4 "foo"`,
+			"",
+		}, "\n"),
+		b.String(),
+	)
+}
+
+func TestSetJSONTraceWriter(t *testing.T) {
+	s := parcom.NewState("foo")
+	b := &bytes.Buffer{}
+	s.SetJSONTraceWriter(b)
+	_, err := s.Named("foo", s.Str("foo"))()
+
+	assert.Nil(t, err)
+
+	es := []parcom.TraceEvent{}
+
+	for _, l := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		e := parcom.TraceEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(l), &e))
+		es = append(es, e)
+	}
+
+	assert.Equal(
+		t,
+		[]parcom.TraceEvent{
+			{Kind: parcom.TraceEnter, Name: "foo", Position: parcom.Position{Offset: 0, Line: 1, Column: 1}},
+			{
+				Kind:     parcom.TraceSuccess,
+				Name:     "foo",
+				Position: parcom.Position{Offset: 3, Line: 1, Column: 4},
+				Consumed: "foo",
+			},
+		},
+		es,
+	)
+}
+
+func TestSetTraceFuncWithFailure(t *testing.T) {
+	s := parcom.NewState("bar")
+	es := []parcom.TraceEvent{}
+	s.SetTraceFunc(func(e parcom.TraceEvent) { es = append(es, e) })
+	_, err := s.Named("foo", s.Str("foo"))()
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, len(es))
+	assert.Equal(t, parcom.TraceFailure, es[1].Kind)
+	assert.Equal(t, "invalid character 'b'", es[1].Error)
+}
+
+func TestSetTraceFuncWithPositionalState(t *testing.T) {
+	s := parcom.NewPositionalState("foo\n  bar")
+	es := []parcom.TraceEvent{}
+	s.SetTraceFunc(func(e parcom.TraceEvent) {
+		if e.Kind == parcom.TraceEnter {
+			es = append(es, e)
+		}
+	})
+	s.SetTrivia(s.Space())
+	_, err := s.WithBlock(s.Symbol("foo"), s.Named("bar", s.Symbol("bar")))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(es))
+	assert.Equal(t, 2, es[0].Position.Line)
+	assert.Equal(t, 2, es[0].SavedLine)
+	assert.Equal(t, 3, es[0].SavedColumn)
+}
+
+func TestNamedWithoutTracing(t *testing.T) {
+	s := parcom.NewState("foo")
+	x, err := s.Named("foo", s.Str("foo"))()
+
+	assert.Equal(t, "foo", x)
+	assert.Nil(t, err)
+}
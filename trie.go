@@ -0,0 +1,80 @@
+package parcom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type trie struct {
+	children map[rune]*trie
+	value    interface{}
+	terminal bool
+}
+
+func newTrie() *trie {
+	return &trie{map[rune]*trie{}, nil, false}
+}
+
+func (t *trie) insert(str string, x interface{}) {
+	for _, r := range str {
+		if _, ok := t.children[r]; !ok {
+			t.children[r] = newTrie()
+		}
+
+		t = t.children[r]
+	}
+
+	t.value = x
+	t.terminal = true
+}
+
+// OneOfStr creates a parser to parse the longest one of given strings. Its
+// result is a parsed string.
+func (s *State) OneOfStr(strs ...string) Parser {
+	m := make(map[string]interface{}, len(strs))
+
+	for _, str := range strs {
+		m[str] = str
+	}
+
+	return s.OneOfStrMap(m)
+}
+
+// OneOfStrMap creates a parser to parse the longest one of keys in a given map.
+// Its result is a value corresponding to a parsed key.
+func (s *State) OneOfStrMap(m map[string]interface{}) Parser {
+	t := newTrie()
+	ks := make([]string, 0, len(m))
+
+	for k, v := range m {
+		t.insert(k, v)
+		ks = append(ks, fmt.Sprintf("%q", k))
+	}
+
+	sort.Strings(ks)
+	e := "expected one of " + strings.Join(ks, ", ")
+
+	return func() (interface{}, error) {
+		ss := *s
+		x, ok := t.value, t.terminal
+
+		for u := t; !s.exhausted() && u.children[s.currentRune()] != nil; {
+			u = u.children[s.currentRune()]
+			s.readRune()
+
+			if u.terminal {
+				x, ok = u.value, true
+				ss = *s
+			}
+		}
+
+		*s = ss
+
+		if !ok {
+			return nil, NewError(e, s)
+		}
+
+		return x, nil
+	}
+}
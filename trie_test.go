@@ -0,0 +1,68 @@
+package parcom_test
+
+import (
+	"testing"
+
+	"github.com/raviqqe/parcom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOneOfStr(t *testing.T) {
+	for _, str := range []string{"=", "==", "===", "=>"} {
+		s := parcom.NewState(str)
+		x, err := s.Exhaust(s.OneOfStr("=>", "=", "===", "=="))()
+
+		assert.Equal(t, str, x)
+		assert.Nil(t, err)
+	}
+}
+
+func TestOneOfStrWithLongestMatch(t *testing.T) {
+	s := parcom.NewState("==!")
+	x, err := s.OneOfStr("=", "==", "===")()
+
+	assert.Equal(t, "==", x)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.Column())
+}
+
+func TestOneOfStrWithPartialMatch(t *testing.T) {
+	s := parcom.NewState("for")
+	x, err := s.OneOfStr("f", "foo")()
+
+	assert.Equal(t, "f", x)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Column())
+}
+
+func TestOneOfStrError(t *testing.T) {
+	s := parcom.NewState("fob")
+	_, err := s.OneOfStr("foo", "bar")()
+
+	assert.Error(t, err)
+	assert.Equal(t, `expected one of "bar", "foo"`, err.Error())
+	assert.Equal(t, 1, err.(parcom.Error).Column())
+}
+
+func TestOneOfStrErrorWithEndOfSource(t *testing.T) {
+	s := parcom.NewState("")
+	_, err := s.OneOfStr("foo")()
+
+	assert.Equal(t, "unexpected end of source", err.Error())
+}
+
+func TestOneOfStrMap(t *testing.T) {
+	s := parcom.NewState("<=")
+	x, err := s.Exhaust(s.OneOfStrMap(map[string]interface{}{"<": 1, "<=": 2, "<<": 3}))()
+
+	assert.Equal(t, 2, x)
+	assert.Nil(t, err)
+}
+
+func TestOneOfStrMultipleTimes(t *testing.T) {
+	s := parcom.NewState("==>=")
+	x, err := s.Exhaust(s.Many(s.OneOfStr("=", "==", ">=")))()
+
+	assert.Equal(t, []interface{}{"==", ">="}, x)
+	assert.Nil(t, err)
+}